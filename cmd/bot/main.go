@@ -10,6 +10,9 @@ import (
 
 	"github.com/jus1d/kypidbot/internal/config"
 	"github.com/jus1d/kypidbot/internal/delivery/telegram"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/infrastructure/disk"
 	"github.com/jus1d/kypidbot/internal/infrastructure/ollama"
 	"github.com/jus1d/kypidbot/internal/infrastructure/s3"
 	"github.com/jus1d/kypidbot/internal/lib/logger/daily"
@@ -66,13 +69,25 @@ func main() {
 
 	slog.Info("postgresql: ok")
 
-	s3с, err := s3.New(&c.S3)
-	if err != nil {
-		slog.Error("s3: failed to connect", sl.Err(err))
-		os.Exit(1)
-	}
+	var photos domain.PhotoStore
+	switch c.Photos.Storage {
+	case config.PhotoStorageDisk:
+		photos, err = disk.New(c.Photos.Dir)
+		if err != nil {
+			slog.Error("disk: failed to open photos dir", slog.String("dir", c.Photos.Dir), sl.Err(err))
+			os.Exit(1)
+		}
+
+		slog.Info("disk: ok", slog.String("dir", c.Photos.Dir))
+	default:
+		photos, err = s3.New(&c.S3)
+		if err != nil {
+			slog.Error("s3: failed to connect", sl.Err(err))
+			os.Exit(1)
+		}
 
-	slog.Info("s3: ok")
+		slog.Info("s3: ok")
+	}
 
 	userRepo := postgres.NewUserRepo(db)
 	placeRepo := postgres.NewPlaceRepo(db)
@@ -80,11 +95,31 @@ func main() {
 	userMessageRepo := postgres.NewUserMessageRepo(db)
 	feedbackRepo := postgres.NewFeedbackRepo(db)
 	settingsRepo := postgres.NewSettingsRepo(db)
-
-	registration := usecase.NewRegistration(userRepo)
-	admin := usecase.NewAdmin(userRepo, meetingRepo)
-	matching := usecase.NewMatching(userRepo, meetingRepo, ollama)
-	meeting := usecase.NewMeeting(userRepo, placeRepo, meetingRepo)
+	proposalRepo := postgres.NewProposalRepo(db)
+	relayRepo := postgres.NewRelayRepo(db)
+	checkInRepo := postgres.NewCheckInRepo(db)
+	reliabilityRepo := postgres.NewReliabilityRepo(db)
+	deliveryRepo := postgres.NewDeliveryRepo(db)
+	ratingRepo := postgres.NewRatingRepo(db)
+	reportRepo := postgres.NewReportRepo(db)
+	blockRepo := postgres.NewBlockRepo(db)
+	incidentRepo := postgres.NewIncidentRepo(db)
+	outboxRepo := postgres.NewOutboxRepo(db)
+	broadcastRepo := postgres.NewBroadcastRepo(db)
+	jobRepo := postgres.NewJobRepo(db)
+
+	registration := usecase.NewRegistration(userRepo, c.About.MinLength, c.About.MaxLength, c.About.FollowUpLength)
+	reliability := usecase.NewReliability(reliabilityRepo, c.Matching.NoShowAfter, c.Matching.LateCancelWithin, c.Matching.PenaltyWeight, c.Matching.ExcludePenalty)
+	admin := usecase.NewAdmin(userRepo, meetingRepo, ratingRepo, outboxRepo, jobRepo, reliability)
+	matching := usecase.NewMatching(userRepo, meetingRepo, blockRepo, reliability, ollama)
+	meeting := usecase.NewMeeting(userRepo, placeRepo, meetingRepo, proposalRepo, checkInRepo)
+	places := usecase.NewPlaces(userRepo, placeRepo, photos)
+	relay := usecase.NewRelay(userRepo, meetingRepo, relayRepo, blockRepo, c.Relay.RateLimit, c.Relay.RateWindow, c.Relay.CloseAfter)
+	rating := usecase.NewRating(meetingRepo, ratingRepo, feedbackRepo, deliveryRepo)
+	moderation := usecase.NewModeration(userRepo, meetingRepo, reportRepo, blockRepo)
+	safety := usecase.NewSafety(userRepo, meetingRepo, placeRepo, incidentRepo, c.Safety.OrganizerChatID)
+	broadcasts := usecase.NewBroadcasts(userRepo, broadcastRepo)
+	preferences := usecase.NewPreferences(userRepo)
 
 	bot, err := telegram.NewBot(
 		c.Env,
@@ -93,12 +128,19 @@ func main() {
 		admin,
 		matching,
 		meeting,
+		places,
+		relay,
+		rating,
+		moderation,
+		safety,
+		broadcasts,
+		preferences,
 		userRepo,
 		userMessageRepo,
-		feedbackRepo,
 		settingsRepo,
+		outboxRepo,
 		placeRepo,
-		s3с,
+		photos,
 	)
 	if err != nil {
 		slog.Error("failed to create the bot", sl.Err(err))
@@ -108,13 +150,22 @@ func main() {
 	bot.Setup()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	notificator := notifications.New(&c.Notifications, bot.TeleBot(), userRepo, placeRepo, meetingRepo, settingsRepo)
-	notificator.Register(notificator.MeetingReminder)
-	notificator.Register(notificator.RegisterReminder)
-	notificator.Register(notificator.InviteReminder)
-
+	notificator := notifications.New(&c.Notifications, bot.TeleBot(), bot.Invites(), bot.Outbox(), userRepo, placeRepo, meetingRepo, deliveryRepo, settingsRepo, matching, meeting, broadcasts, jobRepo)
+	notificator.Register("meeting_reminder", notificator.MeetingReminder)
+	notificator.Register("register_reminder", notificator.RegisterReminder)
+	notificator.Register("invite_reminder", notificator.InviteReminder)
+	notificator.Register("confirmation_deadline", notificator.ConfirmationDeadline)
+	notificator.Register("rematch", notificator.Rematch)
+	notificator.Register("see_again", notificator.SeeAgain)
+	notificator.Register("feedback_request", notificator.FeedbackRequest)
+	notificator.Register("broadcasts", notificator.Broadcasts)
+
+	worker := outbox.NewWorker(&c.Outbox, bot.Photos(), userRepo, userMessageRepo, outboxRepo)
+
+	go worker.Run(ctx)
 	go notificator.Run(ctx)
 	go bot.Start(ctx)
+	slog.Info("outbox: ok", slog.String("poll_interval", c.Outbox.PollInterval.String()))
 	slog.Info("notifications: ok", slog.String("poll_interval", c.Notifications.PollInterval.String()))
 
 	<-stop
@@ -101,7 +101,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	places, err := placeRepo.GetAllPlaces(ctx)
+	places, err := placeRepo.GetActivePlaces(ctx)
 	if err != nil {
 		slog.Error("failed to get places", sl.Err(err))
 		os.Exit(1)
@@ -7,6 +7,8 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/cron"
 )
 
 const (
@@ -20,8 +22,14 @@ type Config struct {
 	Bot           Bot           `yaml:"bot" env-required:"true"`
 	Ollama        Ollama        `yaml:"ollama" env-required:"true"`
 	Postgres      Postgres      `yaml:"postgres" env-required:"true"`
-	S3            S3            `yaml:"s3" env-required:"true"`
+	Photos        Photos        `yaml:"photos"`
+	S3            S3            `yaml:"s3"`
 	Notifications Notifications `yaml:"notifications"`
+	Relay         Relay         `yaml:"relay"`
+	Matching      Matching      `yaml:"matching"`
+	Safety        Safety        `yaml:"safety"`
+	Outbox        Outbox        `yaml:"outbox"`
+	About         About         `yaml:"about"`
 }
 
 type Bot struct {
@@ -29,11 +37,106 @@ type Bot struct {
 	MessagesPath string `yaml:"messages_path" env-required:"true"`
 }
 
+// Notifications configures the notification jobs. A job without its own
+// schedule in Jobs runs every PollInterval. Non-urgent notifications are
+// deferred during QuietHours.
 type Notifications struct {
-	PollInterval           time.Duration `yaml:"poll_interval" env-default:"5s"`
-	DateUpcomingIn         time.Duration `yaml:"date_upcoming_in" env-default:"1h"`
-	RegistrationReminderIn time.Duration `yaml:"registration_reminder_in" env-default:"24h"`
-	InviteReminderIn       time.Duration `yaml:"invite_reminder_in" env-default:"10m"`
+	PollInterval           time.Duration  `yaml:"poll_interval" env-default:"5s"`
+	Jobs                   map[string]Job `yaml:"jobs"`
+	QuietHours             QuietHours     `yaml:"quiet_hours"`
+	MeetingReminders       []Reminder     `yaml:"meeting_reminders"`
+	RegistrationReminderIn time.Duration  `yaml:"registration_reminder_in" env-default:"24h"`
+	InviteReminderIn       time.Duration  `yaml:"invite_reminder_in" env-default:"10m"`
+	ConfirmationDeadline   time.Duration  `yaml:"confirmation_deadline" env-default:"3h"`
+	SeeAgainAfter          time.Duration  `yaml:"see_again_after" env-default:"3h"`
+	FeedbackAfter          time.Duration  `yaml:"feedback_after" env-default:"24h"`
+}
+
+// Job is the schedule of a notification job: every Interval, or at the times
+// of the five-field Cron spec in the event's timezone, which takes precedence.
+// Each run is delayed by a random duration up to Jitter.
+type Job struct {
+	Interval time.Duration `yaml:"interval"`
+	Cron     string        `yaml:"cron"`
+	Jitter   time.Duration `yaml:"jitter"`
+}
+
+// QuietHours is the time of day from From till To, as "15:04" in the event's
+// timezone. It may wrap past midnight; empty or equal bounds disable it.
+type QuietHours struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// Contains reports whether t falls within the quiet hours.
+func (q QuietHours) Contains(t time.Time) bool {
+	from, err := time.Parse("15:04", q.From)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse("15:04", q.To)
+	if err != nil {
+		return false
+	}
+
+	minute := func(t time.Time) int { return t.Hour()*60 + t.Minute() }
+	now, start, end := minute(t), minute(from), minute(to)
+
+	if start <= end {
+		return start <= now && now < end
+	}
+	return now >= start || now < end
+}
+
+// Reminder is a meeting reminder sent Before the meeting starts, with the text
+// taken from the notifications.reminders.templates message of the Template key.
+type Reminder struct {
+	Before   time.Duration `yaml:"before"`
+	Template string        `yaml:"template"`
+}
+
+type Relay struct {
+	RateLimit  int           `yaml:"rate_limit" env-default:"30"`
+	RateWindow time.Duration `yaml:"rate_window" env-default:"10m"`
+	CloseAfter time.Duration `yaml:"close_after" env-default:"3h"`
+}
+
+// Matching configures how participants' reliability affects matching.
+// A user is excluded from matching once their penalty reaches ExcludePenalty,
+// 0 disables the exclusion.
+type Matching struct {
+	NoShowAfter      time.Duration `yaml:"no_show_after" env-default:"1h"`
+	LateCancelWithin time.Duration `yaml:"late_cancel_within" env-default:"3h"`
+	PenaltyWeight    float64       `yaml:"penalty_weight" env-default:"0.1"`
+	ExcludePenalty   int           `yaml:"exclude_penalty" env-default:"6"`
+}
+
+// Safety configures where participants' calls for help go. With no
+// OrganizerChatID every admin is alerted in private messages.
+type Safety struct {
+	OrganizerChatID int64 `yaml:"organizer_chat_id"`
+}
+
+// Outbox configures delivery of queued messages. GlobalRate is the number of
+// messages sent per second in total, ChatInterval is the minimal time between
+// two messages to the same chat. A failed message is retried after BaseBackoff,
+// doubled with every attempt, until it has failed MaxAttempts times.
+type Outbox struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	BatchSize    int           `yaml:"batch_size" env-default:"50"`
+	GlobalRate   int           `yaml:"global_rate" env-default:"25"`
+	ChatInterval time.Duration `yaml:"chat_interval" env-default:"1s"`
+	MaxAttempts  int           `yaml:"max_attempts" env-default:"5"`
+	BaseBackoff  time.Duration `yaml:"base_backoff" env-default:"5s"`
+}
+
+// About configures validation of the about text. Texts shorter than MinLength
+// or longer than MaxLength characters are rejected, and ones shorter than
+// FollowUpLength get a follow-up question before the schedule step.
+type About struct {
+	MinLength      int `yaml:"min_length" env-default:"10"`
+	MaxLength      int `yaml:"max_length" env-default:"512"`
+	FollowUpLength int `yaml:"follow_up_length" env-default:"80"`
 }
 
 type Ollama struct {
@@ -52,12 +155,23 @@ type Postgres struct {
 	ModeSSL  string `yaml:"sslmode" env-required:"true"`
 }
 
+const (
+	PhotoStorageS3   = "s3"
+	PhotoStorageDisk = "disk"
+)
+
+type Photos struct {
+	Storage string `yaml:"storage" env-default:"s3"`
+	Dir     string `yaml:"dir" env-default:"photos"`
+}
+
+// S3 is required only when photos are stored in S3
 type S3 struct {
-	Host            string `yaml:"host" env-required:"true"`
-	Port            string `yaml:"port" env-required:"true"`
-	AccessKeyID     string `yaml:"access_key_id" env-required:"true"`
-	SecretAccessKey string `yaml:"secret_access_key" env-required:"true"`
-	Bucket          string `yaml:"bucket" env-required:"true"`
+	Host            string `yaml:"host"`
+	Port            string `yaml:"port"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	Bucket          string `yaml:"bucket"`
 	Region          string `yaml:"region" env-default:"us-east-1"`
 	UseSSL          bool   `yaml:"use_ssl" env-default:"false"`
 }
@@ -83,9 +197,85 @@ func MustLoad() *Config {
 		panic("cannot read config: " + err.Error())
 	}
 
+	switch config.Photos.Storage {
+	case PhotoStorageS3:
+		if config.S3.Host == "" || config.S3.Bucket == "" {
+			panic("s3 config is required for photos storage: " + config.Photos.Storage)
+		}
+	case PhotoStorageDisk:
+	default:
+		panic("unknown photos storage: " + config.Photos.Storage)
+	}
+
 	if err = cleanenv.ReadConfig(config.Bot.MessagesPath, &messages.M); err != nil {
 		panic("cannot read messages: " + err.Error())
 	}
 
+	if len(config.Notifications.MeetingReminders) == 0 {
+		config.Notifications.MeetingReminders = []Reminder{{Before: time.Hour, Template: "soon"}}
+	}
+	for _, r := range config.Notifications.MeetingReminders {
+		if r.Before <= 0 {
+			panic("meeting reminder offset must be positive: " + r.Before.String())
+		}
+		if _, ok := messages.M.Notifications.Reminders.Templates[r.Template]; !ok {
+			panic("unknown meeting reminder template: " + r.Template)
+		}
+	}
+
+	for name, job := range config.Notifications.Jobs {
+		if job.Cron != "" {
+			if _, err := cron.Parse(job.Cron); err != nil {
+				panic("invalid schedule of job " + name + ": " + err.Error())
+			}
+		} else if job.Interval < 0 {
+			panic("job interval must not be negative: " + name)
+		}
+	}
+
+	if q := config.Notifications.QuietHours; q.From != "" || q.To != "" {
+		for _, bound := range []string{q.From, q.To} {
+			if _, err := time.Parse("15:04", bound); err != nil {
+				panic("invalid quiet hours bound, want HH:MM: " + bound)
+			}
+		}
+	}
+
+	if a := config.About; a.MinLength < 1 || a.MinLength > a.FollowUpLength || a.FollowUpLength > a.MaxLength {
+		panic("about lengths must satisfy 0 < min_length <= follow_up_length <= max_length")
+	}
+	if config.About.MaxLength > config.Ollama.MaxLength {
+		panic("about max_length must not exceed ollama max_length, or about texts get cut")
+	}
+	if f := messages.M.Profile.About.FollowUps; len(f) == 0 || len(f[len(f)-1].Keywords) > 0 {
+		panic("about follow-up questions must end with one without keywords")
+	}
+
+	for _, tag := range domain.RatingTags {
+		if _, ok := messages.M.Rating.TagLabels[string(tag)]; !ok {
+			panic("missing rating tag label: " + string(tag))
+		}
+	}
+	for _, reason := range domain.ReportReasons {
+		if _, ok := messages.M.Moderation.Reasons[string(reason)]; !ok {
+			panic("missing report reason label: " + string(reason))
+		}
+	}
+	for _, status := range domain.ReportResolutions {
+		if _, ok := messages.M.Moderation.Statuses[string(status)]; !ok {
+			panic("missing report status label: " + string(status))
+		}
+	}
+	for _, category := range domain.NotificationCategories {
+		if _, ok := messages.M.Preferences.Categories[string(category)]; !ok {
+			panic("missing notification category label: " + string(category))
+		}
+	}
+	for _, segment := range domain.BroadcastSegments {
+		if _, ok := messages.M.Broadcast.Segments[string(segment)]; !ok {
+			panic("missing broadcast segment label: " + string(segment))
+		}
+	}
+
 	return &config
 }
@@ -27,6 +27,14 @@ func Format(text string, vars map[string]string) string {
 	return result
 }
 
+// Distance formats a distance in meters for humans.
+func Distance(meters float64) string {
+	if meters < 1000 {
+		return fmt.Sprintf("%.0f м", meters)
+	}
+	return fmt.Sprintf("%.1f км", meters/1000)
+}
+
 func Mention(telegramID int64, firstName string, username string) string {
 	if username != "" {
 		return "@" + username
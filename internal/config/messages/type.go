@@ -15,6 +15,28 @@ type Messages struct {
 	Matching      MatchingSection      `yaml:"matching" env-required:"true"`
 	Meeting       MeetingSection       `yaml:"meeting" env-required:"true"`
 	Feedback      FeedbackSection      `yaml:"feedback" env-required:"true"`
+	Relay         RelaySection         `yaml:"relay" env-required:"true"`
+	Rating        RatingSection        `yaml:"rating" env-required:"true"`
+	Moderation    ModerationSection    `yaml:"moderation" env-required:"true"`
+	Safety        SafetySection        `yaml:"safety" env-required:"true"`
+	Broadcast     BroadcastSection     `yaml:"broadcast" env-required:"true"`
+	Preferences   PreferencesSection   `yaml:"preferences" env-required:"true"`
+}
+
+type RelaySection struct {
+	Started     string          `yaml:"started" env-required:"true"`
+	Left        string          `yaml:"left" env-required:"true"`
+	Incoming    string          `yaml:"incoming" env-required:"true"`
+	RateLimited string          `yaml:"rate_limited" env-required:"true"`
+	Closed      string          `yaml:"closed" env-required:"true"`
+	Log         RelayLogSection `yaml:"log" env-required:"true"`
+}
+
+type RelayLogSection struct {
+	Usage string `yaml:"usage" env-required:"true"`
+	Empty string `yaml:"empty" env-required:"true"`
+	Title string `yaml:"title" env-required:"true"`
+	Entry string `yaml:"entry" env-required:"true"`
 }
 
 type FeedbackSection struct {
@@ -22,6 +44,111 @@ type FeedbackSection struct {
 	ThankYou string `yaml:"thank_you" env-required:"true"`
 }
 
+// RatingSection holds the post-meeting rating texts. TagLabels is keyed by rating tag.
+type RatingSection struct {
+	Ask       string               `yaml:"ask" env-required:"true"`
+	Tags      string               `yaml:"tags" env-required:"true"`
+	TagLabels map[string]string    `yaml:"tag_labels" env-required:"true"`
+	Buttons   RatingButtonsSection `yaml:"buttons" env-required:"true"`
+}
+
+type RatingButtonsSection struct {
+	Done string `yaml:"done" env-required:"true"`
+	Skip string `yaml:"skip" env-required:"true"`
+}
+
+// ModerationSection holds reports, blocks and bans texts. Reasons and Statuses are
+// keyed by report reason and report status.
+type ModerationSection struct {
+	Report         ModerationReportSection  `yaml:"report" env-required:"true"`
+	Reasons        map[string]string        `yaml:"reasons" env-required:"true"`
+	Blocked        string                   `yaml:"blocked" env-required:"true"`
+	Queue          ModerationQueueSection   `yaml:"queue" env-required:"true"`
+	Statuses       map[string]string        `yaml:"statuses" env-required:"true"`
+	Warning        string                   `yaml:"warning" env-required:"true"`
+	Banned         string                   `yaml:"banned" env-required:"true"`
+	PartnerRemoved string                   `yaml:"partner_removed" env-required:"true"`
+	Unban          ModerationUnbanSection   `yaml:"unban" env-required:"true"`
+	Buttons        ModerationButtonsSection `yaml:"buttons" env-required:"true"`
+}
+
+type ModerationReportSection struct {
+	Choose      string `yaml:"choose" env-required:"true"`
+	Sent        string `yaml:"sent" env-required:"true"`
+	Already     string `yaml:"already" env-required:"true"`
+	Cancelled   string `yaml:"cancelled" env-required:"true"`
+	AdminNotice string `yaml:"admin_notice" env-required:"true"`
+}
+
+type ModerationQueueSection struct {
+	Empty           string `yaml:"empty" env-required:"true"`
+	Card            string `yaml:"card" env-required:"true"`
+	Resolved        string `yaml:"resolved" env-required:"true"`
+	AlreadyResolved string `yaml:"already_resolved" env-required:"true"`
+	CannotBanAdmin  string `yaml:"cannot_ban_admin" env-required:"true"`
+}
+
+type ModerationUnbanSection struct {
+	Usage     string `yaml:"usage" env-required:"true"`
+	Success   string `yaml:"success" env-required:"true"`
+	NotBanned string `yaml:"not_banned" env-required:"true"`
+}
+
+type ModerationButtonsSection struct {
+	Report  string `yaml:"report" env-required:"true"`
+	Block   string `yaml:"block" env-required:"true"`
+	Cancel  string `yaml:"cancel" env-required:"true"`
+	Dismiss string `yaml:"dismiss" env-required:"true"`
+	Warn    string `yaml:"warn" env-required:"true"`
+	Ban     string `yaml:"ban" env-required:"true"`
+}
+
+type SafetySection struct {
+	Instructions        string               `yaml:"instructions" env-required:"true"`
+	Alert               string               `yaml:"alert" env-required:"true"`
+	Acknowledged        string               `yaml:"acknowledged" env-required:"true"`
+	AlreadyAcknowledged string               `yaml:"already_acknowledged" env-required:"true"`
+	Buttons             SafetyButtonsSection `yaml:"buttons" env-required:"true"`
+}
+
+type SafetyButtonsSection struct {
+	Help        string `yaml:"help" env-required:"true"`
+	Acknowledge string `yaml:"acknowledge" env-required:"true"`
+}
+
+// BroadcastSection holds the admin broadcast texts. Segments is keyed by broadcast segment.
+type BroadcastSection struct {
+	AskContent       string                  `yaml:"ask_content" env-required:"true"`
+	AskSegment       string                  `yaml:"ask_segment" env-required:"true"`
+	Segments         map[string]string       `yaml:"segments" env-required:"true"`
+	Preview          string                  `yaml:"preview" env-required:"true"`
+	PreviewFailed    string                  `yaml:"preview_failed" env-required:"true"`
+	NoRecipients     string                  `yaml:"no_recipients" env-required:"true"`
+	AskTime          string                  `yaml:"ask_time" env-required:"true"`
+	InvalidTime      string                  `yaml:"invalid_time" env-required:"true"`
+	Scheduled        string                  `yaml:"scheduled" env-required:"true"`
+	Started          string                  `yaml:"started" env-required:"true"`
+	Cancelled        string                  `yaml:"cancelled" env-required:"true"`
+	AlreadyProcessed string                  `yaml:"already_processed" env-required:"true"`
+	Progress         string                  `yaml:"progress" env-required:"true"`
+	Done             string                  `yaml:"done" env-required:"true"`
+	Buttons          BroadcastButtonsSection `yaml:"buttons" env-required:"true"`
+}
+
+type BroadcastButtonsSection struct {
+	Send     string `yaml:"send" env-required:"true"`
+	Schedule string `yaml:"schedule" env-required:"true"`
+	Cancel   string `yaml:"cancel" env-required:"true"`
+}
+
+// PreferencesSection holds the notification settings texts. Categories is keyed by notification category.
+type PreferencesSection struct {
+	Title      string            `yaml:"title" env-required:"true"`
+	Categories map[string]string `yaml:"categories" env-required:"true"`
+	On         string            `yaml:"on" env-required:"true"`
+	Off        string            `yaml:"off" env-required:"true"`
+}
+
 type BotSection struct {
 	Start        StartSection        `yaml:"start" env-required:"true"`
 	Profile      ProfileSection      `yaml:"profile" env-required:"true"`
@@ -33,20 +160,63 @@ type StartSection struct {
 }
 
 type NotificationsSection struct {
-	Remind         string `yaml:"remind" env-required:"true"`
-	Registration   string `yaml:"registration" env-required:"true"`
-	Invite         string `yaml:"invite" env-required:"true"`
-	MeetingSoon    string `yaml:"meeting_soon" env-required:"true"`
-	ArrivedAsk     string `yaml:"arrived_ask" env-required:"true"`
-	ArrivedPartner string `yaml:"arrived_partner" env-required:"true"`
-	CantFindNoted  string `yaml:"cant_find_noted" env-required:"true"`
-	CantFindBoth   string `yaml:"cant_find_both" env-required:"true"`
+	Remind         string           `yaml:"remind" env-required:"true"`
+	Registration   string           `yaml:"registration" env-required:"true"`
+	Invite         string           `yaml:"invite" env-required:"true"`
+	Reminders      RemindersSection `yaml:"reminders" env-required:"true"`
+	ArrivedAsk     string           `yaml:"arrived_ask" env-required:"true"`
+	ArrivedPartner string           `yaml:"arrived_partner" env-required:"true"`
+	CantFindNoted  string           `yaml:"cant_find_noted" env-required:"true"`
+	CantFindBoth   string           `yaml:"cant_find_both" env-required:"true"`
+	SeeAgain       SeeAgainSection  `yaml:"see_again" env-required:"true"`
+	CheckIn        CheckInSection   `yaml:"check_in" env-required:"true"`
+}
+
+// RemindersSection holds meeting reminder texts keyed by the template names
+// used in the config. Templates may use {time} and {place}.
+type RemindersSection struct {
+	Templates   map[string]string `yaml:"templates" env-required:"true"`
+	Unconfirmed string            `yaml:"unconfirmed" env-required:"true"`
+}
+
+type CheckInSection struct {
+	Ask             string `yaml:"ask" env-required:"true"`
+	TooFar          string `yaml:"too_far" env-required:"true"`
+	PartnerDistance string `yaml:"partner_distance" env-required:"true"`
+}
+
+type SeeAgainSection struct {
+	Ask      string `yaml:"ask" env-required:"true"`
+	YesNoted string `yaml:"yes_noted" env-required:"true"`
+	NoNoted  string `yaml:"no_noted" env-required:"true"`
+	Mutual   string `yaml:"mutual" env-required:"true"`
 }
 
 type ProfileSection struct {
 	Sex      SexOnboardingSection `yaml:"sex" env-required:"true"`
 	About    AboutSection         `yaml:"about" env-required:"true"`
 	Schedule ScheduleSection      `yaml:"schedule" env-required:"true"`
+	View     ProfileViewSection   `yaml:"view" env-required:"true"`
+}
+
+type ProfileViewSection struct {
+	Card     string                `yaml:"card" env-required:"true"`
+	NotSet   string                `yaml:"not_set" env-required:"true"`
+	Statuses ProfileStatusSection  `yaml:"statuses" env-required:"true"`
+	AskTime  string                `yaml:"ask_time" env-required:"true"`
+	Buttons  ProfileButtonsSection `yaml:"buttons" env-required:"true"`
+}
+
+type ProfileStatusSection struct {
+	Participating string `yaml:"participating" env-required:"true"`
+	OptedOut      string `yaml:"opted_out" env-required:"true"`
+	NotRegistered string `yaml:"not_registered" env-required:"true"`
+}
+
+type ProfileButtonsSection struct {
+	Sex   string `yaml:"sex" env-required:"true"`
+	About string `yaml:"about" env-required:"true"`
+	Time  string `yaml:"time" env-required:"true"`
 }
 
 type SexOnboardingSection struct {
@@ -55,8 +225,19 @@ type SexOnboardingSection struct {
 }
 
 type AboutSection struct {
-	Request  string `yaml:"request" env-required:"true"`
-	Accepted string `yaml:"accepted" env-required:"true"`
+	Request   string     `yaml:"request" env-required:"true"`
+	Accepted  string     `yaml:"accepted" env-required:"true"`
+	TooShort  string     `yaml:"too_short" env-required:"true"`
+	TooLong   string     `yaml:"too_long" env-required:"true"`
+	NoText    string     `yaml:"no_text" env-required:"true"`
+	FollowUps []FollowUp `yaml:"follow_ups" env-required:"true"`
+}
+
+// FollowUp is a question about a topic the about text may miss. The text
+// mentions the topic if it contains any of the keywords.
+type FollowUp struct {
+	Keywords []string `yaml:"keywords"`
+	Question string   `yaml:"question" env-required:"true"`
 }
 
 type ScheduleSection struct {
@@ -112,6 +293,17 @@ type ButtonsSection struct {
 	CantFind       string     `yaml:"cant_find" env-required:"true"`
 	OptOut         string     `yaml:"opt_out" env-required:"true"`
 	OptIn          string     `yaml:"opt_in" env-required:"true"`
+	ProposeTime    string     `yaml:"propose_time" env-required:"true"`
+	KeepTime       string     `yaml:"keep_time" env-required:"true"`
+	AcceptTime     string     `yaml:"accept_time" env-required:"true"`
+	DeclineTime    string     `yaml:"decline_time" env-required:"true"`
+	SeeAgainYes    string     `yaml:"see_again_yes" env-required:"true"`
+	SeeAgainNo     string     `yaml:"see_again_no" env-required:"true"`
+	ChatPartner    string     `yaml:"chat_partner" env-required:"true"`
+	LeaveChat      string     `yaml:"leave_chat" env-required:"true"`
+	Reply          string     `yaml:"reply" env-required:"true"`
+	ShareLocation  string     `yaml:"share_location" env-required:"true"`
+	SkipLocation   string     `yaml:"skip_location" env-required:"true"`
 }
 
 type SexButtons struct {
@@ -128,11 +320,68 @@ type StickerSection struct {
 }
 
 type AdminSection struct {
-	Promote            AdminCommand `yaml:"promote" env-required:"true"`
-	Demote             AdminCommand `yaml:"demote" env-required:"true"`
-	StartedLog         string       `yaml:"started_log" env-required:"true"`
-	RegistrationClosed string       `yaml:"registration_closed" env-required:"true"`
-	RegistrationOpened string       `yaml:"registration_opened" env-required:"true"`
+	Promote            AdminCommand  `yaml:"promote" env-required:"true"`
+	Demote             AdminCommand  `yaml:"demote" env-required:"true"`
+	StartedLog         string        `yaml:"started_log" env-required:"true"`
+	RegistrationClosed string        `yaml:"registration_closed" env-required:"true"`
+	RegistrationOpened string        `yaml:"registration_opened" env-required:"true"`
+	Places             PlacesSection `yaml:"places" env-required:"true"`
+	User               UserSection   `yaml:"user" env-required:"true"`
+	Jobs               JobsSection   `yaml:"jobs" env-required:"true"`
+}
+
+type JobsSection struct {
+	Header  string `yaml:"header" env-required:"true"`
+	Empty   string `yaml:"empty" env-required:"true"`
+	Item    string `yaml:"item" env-required:"true"`
+	Never   string `yaml:"never" env-required:"true"`
+	Running string `yaml:"running" env-required:"true"`
+	OK      string `yaml:"ok" env-required:"true"`
+	Failed  string `yaml:"failed" env-required:"true"`
+}
+
+type UserSection struct {
+	Usage    string `yaml:"usage" env-required:"true"`
+	Card     string `yaml:"card" env-required:"true"`
+	Matched  string `yaml:"matched" env-required:"true"`
+	Excluded string `yaml:"excluded" env-required:"true"`
+}
+
+type PlacesSection struct {
+	AddUsage        string              `yaml:"add_usage" env-required:"true"`
+	Empty           string              `yaml:"empty" env-required:"true"`
+	Card            string              `yaml:"card" env-required:"true"`
+	Active          string              `yaml:"active" env-required:"true"`
+	Inactive        string              `yaml:"inactive" env-required:"true"`
+	Ask             PlaceAskSection     `yaml:"ask" env-required:"true"`
+	InvalidQuality  string              `yaml:"invalid_quality" env-required:"true"`
+	InvalidLocation string              `yaml:"invalid_location" env-required:"true"`
+	InvalidRadius   string              `yaml:"invalid_radius" env-required:"true"`
+	ExpectedPhoto   string              `yaml:"expected_photo" env-required:"true"`
+	Saved           string              `yaml:"saved" env-required:"true"`
+	EditCancelled   string              `yaml:"edit_cancelled" env-required:"true"`
+	Buttons         PlaceButtonsSection `yaml:"buttons" env-required:"true"`
+}
+
+type PlaceAskSection struct {
+	Description string `yaml:"description" env-required:"true"`
+	Route       string `yaml:"route" env-required:"true"`
+	Quality     string `yaml:"quality" env-required:"true"`
+	Photo       string `yaml:"photo" env-required:"true"`
+	Location    string `yaml:"location" env-required:"true"`
+	Radius      string `yaml:"radius" env-required:"true"`
+}
+
+type PlaceButtonsSection struct {
+	Description string `yaml:"description" env-required:"true"`
+	Route       string `yaml:"route" env-required:"true"`
+	Quality     string `yaml:"quality" env-required:"true"`
+	Photo       string `yaml:"photo" env-required:"true"`
+	Location    string `yaml:"location" env-required:"true"`
+	Radius      string `yaml:"radius" env-required:"true"`
+	Disable     string `yaml:"disable" env-required:"true"`
+	Enable      string `yaml:"enable" env-required:"true"`
+	Cancel      string `yaml:"cancel" env-required:"true"`
 }
 
 type AdminCommand struct {
@@ -170,12 +419,22 @@ type MatchingSuccess struct {
 	Matched      string `yaml:"matched" env-required:"true"`
 	MeetingsSent string `yaml:"meetings_sent" env-required:"true"`
 	NotMatched   string `yaml:"not_matched" env-required:"true"`
+	NotRematched string `yaml:"not_rematched" env-required:"true"`
 }
 
 type MeetingSection struct {
-	Invite  MeetingInviteSection  `yaml:"invite" env-required:"true"`
-	Status  MeetingStatusSection  `yaml:"status" env-required:"true"`
-	Special MeetingSpecialSection `yaml:"special" env-required:"true"`
+	Invite   MeetingInviteSection   `yaml:"invite" env-required:"true"`
+	Status   MeetingStatusSection   `yaml:"status" env-required:"true"`
+	Special  MeetingSpecialSection  `yaml:"special" env-required:"true"`
+	Proposal MeetingProposalSection `yaml:"proposal" env-required:"true"`
+	Calendar MeetingCalendarSection `yaml:"calendar" env-required:"true"`
+}
+
+type MeetingCalendarSection struct {
+	Summary           string `yaml:"summary" env-required:"true"`
+	Caption           string `yaml:"caption" env-required:"true"`
+	CancelCaption     string `yaml:"cancel_caption" env-required:"true"`
+	RescheduleCaption string `yaml:"reschedule_caption" env-required:"true"`
 }
 
 type MeetingInviteSection struct {
@@ -189,6 +448,21 @@ type MeetingStatusSection struct {
 	BothConfirmed    string `yaml:"both_confirmed" env-required:"true"`
 	Cancelled        string `yaml:"cancelled" env-required:"true"`
 	PartnerCancelled string `yaml:"partner_cancelled" env-required:"true"`
+	Expired          string `yaml:"expired" env-required:"true"`
+	PartnerExpired   string `yaml:"partner_expired" env-required:"true"`
+	Outdated         string `yaml:"outdated" env-required:"true"`
+}
+
+type MeetingProposalSection struct {
+	Choose      string `yaml:"choose" env-required:"true"`
+	NoSlots     string `yaml:"no_slots" env-required:"true"`
+	Sent        string `yaml:"sent" env-required:"true"`
+	Offer       string `yaml:"offer" env-required:"true"`
+	Accepted    string `yaml:"accepted" env-required:"true"`
+	Declined    string `yaml:"declined" env-required:"true"`
+	YouDeclined string `yaml:"you_declined" env-required:"true"`
+	NoFreePlace string `yaml:"no_free_place" env-required:"true"`
+	Outdated    string `yaml:"outdated" env-required:"true"`
 }
 
 type MeetingSpecialSection struct {
@@ -9,9 +9,11 @@ import (
 	"github.com/jus1d/kypidbot/internal/config/messages"
 	"github.com/jus1d/kypidbot/internal/delivery/telegram/callback"
 	"github.com/jus1d/kypidbot/internal/delivery/telegram/command"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/invite"
 	"github.com/jus1d/kypidbot/internal/delivery/telegram/message"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/placephoto"
 	"github.com/jus1d/kypidbot/internal/domain"
-	"github.com/jus1d/kypidbot/internal/infrastructure/s3"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
 	"github.com/jus1d/kypidbot/internal/usecase"
 	"github.com/jus1d/kypidbot/internal/version"
@@ -25,15 +27,22 @@ type Bot struct {
 	admin        *usecase.Admin
 	matching     *usecase.Matching
 	meeting      *usecase.Meeting
+	places       *usecase.Places
+	relay        *usecase.Relay
+	rating       *usecase.Rating
+	moderation   *usecase.Moderation
+	safety       *usecase.Safety
+	broadcasts   *usecase.Broadcasts
+	preferences  *usecase.Preferences
 	users        domain.UserRepository
 	userMessages domain.UserMessageRepository
-	feedback     domain.FeedbackRepository
 	settings     domain.SettingsRepository
-	places       domain.PlaceRepository
-	s3           *s3.Client
+	photos       *placephoto.Sender
+	outbox       *outbox.Sender
+	invites      *invite.Sender
 }
 
-func NewBot(env string, token string, registration *usecase.Registration, admin *usecase.Admin, matching *usecase.Matching, meeting *usecase.Meeting, users domain.UserRepository, userMessages domain.UserMessageRepository, feedback domain.FeedbackRepository, settings domain.SettingsRepository, places domain.PlaceRepository, s3Client *s3.Client) (*Bot, error) {
+func NewBot(env string, token string, registration *usecase.Registration, admin *usecase.Admin, matching *usecase.Matching, meeting *usecase.Meeting, places *usecase.Places, relay *usecase.Relay, rating *usecase.Rating, moderation *usecase.Moderation, safety *usecase.Safety, broadcasts *usecase.Broadcasts, preferences *usecase.Preferences, users domain.UserRepository, userMessages domain.UserMessageRepository, settings domain.SettingsRepository, outboxRepo domain.OutboxRepository, placeRepo domain.PlaceRepository, photos domain.PhotoStore) (*Bot, error) {
 	pref := tele.Settings{
 		Token:     token,
 		Poller:    &tele.LongPoller{Timeout: 10 * time.Second},
@@ -45,6 +54,14 @@ func NewBot(env string, token string, registration *usecase.Registration, admin
 		return nil, err
 	}
 
+	sender := &placephoto.Sender{
+		Bot:    bot,
+		Store:  photos,
+		Places: placeRepo,
+	}
+
+	queue := &outbox.Sender{Repo: outboxRepo}
+
 	return &Bot{
 		env:          env,
 		bot:          bot,
@@ -52,12 +69,23 @@ func NewBot(env string, token string, registration *usecase.Registration, admin
 		admin:        admin,
 		matching:     matching,
 		meeting:      meeting,
+		places:       places,
+		relay:        relay,
+		rating:       rating,
+		moderation:   moderation,
+		safety:       safety,
+		broadcasts:   broadcasts,
+		preferences:  preferences,
 		users:        users,
 		userMessages: userMessages,
-		feedback:     feedback,
 		settings:     settings,
-		places:       places,
-		s3:           s3Client,
+		photos:       sender,
+		outbox:       queue,
+		invites: &invite.Sender{
+			Photos:       sender,
+			Outbox:       queue,
+			UserMessages: userMessages,
+		},
 	}, nil
 }
 
@@ -67,28 +95,47 @@ func (b *Bot) Setup() {
 		Admin:        b.admin,
 		Matching:     b.matching,
 		Meeting:      b.meeting,
-		Settings:     b.settings,
 		Places:       b.places,
+		Relay:        b.relay,
+		Rating:       b.rating,
+		Moderation:   b.moderation,
+		Broadcasts:   b.broadcasts,
+		Preferences:  b.preferences,
+		Settings:     b.settings,
 		Bot:          b.bot,
-		S3:           b.s3,
+		Photos:       b.photos,
+		Outbox:       b.outbox,
+		Invites:      b.invites,
 	}
 
 	cb := &callback.Handler{
 		Registration: b.registration,
 		Admin:        b.admin,
 		Meeting:      b.meeting,
+		Places:       b.places,
+		Relay:        b.relay,
+		Rating:       b.rating,
+		Moderation:   b.moderation,
+		Broadcasts:   b.broadcasts,
+		Preferences:  b.preferences,
+		Safety:       b.safety,
 		Users:        b.users,
 		UserMessages: b.userMessages,
 		Bot:          b.bot,
-		S3:           b.s3,
+		Photos:       b.photos,
+		Invites:      b.invites,
 	}
 
 	msg := &message.Handler{
 		Registration: b.registration,
 		Meeting:      b.meeting,
+		Places:       b.places,
+		Relay:        b.relay,
+		Rating:       b.rating,
+		Broadcasts:   b.broadcasts,
 		Users:        b.users,
-		Feedback:     b.feedback,
 		Bot:          b.bot,
+		Photos:       b.photos,
 	}
 
 	btnSexMale := tele.Btn{Unique: "sex_male"}
@@ -103,15 +150,49 @@ func (b *Bot) Setup() {
 	btnArrivedMeeting := tele.Btn{Unique: "arrived_meeting"}
 	btnCantFindPartner := tele.Btn{Unique: "cant_find_partner"}
 	btnOptOut := tele.Btn{Unique: "opt_out"}
+	btnProfileEdit := tele.Btn{Unique: "profile_edit"}
+	btnProfileParticipation := tele.Btn{Unique: "profile_participation"}
 	btnRefreshAdmin := tele.Btn{Unique: "refresh_admin"}
+	btnPlacesPage := tele.Btn{Unique: "places_page"}
+	btnPlaceEdit := tele.Btn{Unique: "place_edit"}
+	btnPlaceToggle := tele.Btn{Unique: "place_toggle"}
+	btnCancelPlaceEdit := tele.Btn{Unique: "cancel_place_edit"}
+	btnProposeTime := tele.Btn{Unique: "propose_time"}
+	btnProposeSlot := tele.Btn{Unique: "propose_slot"}
+	btnKeepTime := tele.Btn{Unique: "keep_time"}
+	btnAcceptTime := tele.Btn{Unique: "accept_time"}
+	btnDeclineTime := tele.Btn{Unique: "decline_time"}
+	btnSeeAgain := tele.Btn{Unique: "see_again"}
+	btnStartChat := tele.Btn{Unique: "start_chat"}
+	btnLeaveChat := tele.Btn{Unique: "leave_chat"}
+	btnRate := tele.Btn{Unique: "rate"}
+	btnRateTag := tele.Btn{Unique: "rate_tag"}
+	btnNotificationToggle := tele.Btn{Unique: "notification_toggle"}
+	btnRateDone := tele.Btn{Unique: "rate_done"}
+	btnRateSkip := tele.Btn{Unique: "rate_skip"}
+	btnReport := tele.Btn{Unique: "report"}
+	btnReportReason := tele.Btn{Unique: "report_reason"}
+	btnReportCancel := tele.Btn{Unique: "report_cancel"}
+	btnReportResolve := tele.Btn{Unique: "report_resolve"}
+	btnBlock := tele.Btn{Unique: "block"}
+	btnHelp := tele.Btn{Unique: "help"}
+	btnIncidentAck := tele.Btn{Unique: "incident_ack"}
+	btnBroadcastSegment := tele.Btn{Unique: "broadcast_segment"}
+	btnBroadcastSend := tele.Btn{Unique: "broadcast_send"}
+	btnBroadcastSchedule := tele.Btn{Unique: "broadcast_schedule"}
+	btnBroadcastCancel := tele.Btn{Unique: "broadcast_cancel"}
 
 	b.bot.Use(LogUpdates)
+	b.bot.Use(b.Reachability)
+	b.bot.Use(b.BanGuard)
 
 	b.bot.Handle("/start", cmd.Start, b.RegistrationGuard)
 	b.bot.Handle("/invite", cmd.Invite, b.RegistrationGuard)
 	b.bot.Handle("/leaderboard", cmd.Leaderboard)
 	b.bot.Handle("/about", cmd.About)
 	b.bot.Handle("/support", cmd.Support)
+	b.bot.Handle("/notifications", cmd.Notifications)
+	b.bot.Handle("/profile", cmd.Profile)
 
 	b.bot.Handle("/matchpairs", cmd.MatchPairs, b.AdminOnly)
 	b.bot.Handle("/sendinvites", cmd.SendInvites, b.AdminOnly)
@@ -119,28 +200,69 @@ func (b *Bot) Setup() {
 	b.bot.Handle("/promote", cmd.Promote, b.AdminOnly)
 	b.bot.Handle("/demote", cmd.Demote, b.AdminOnly)
 	b.bot.Handle("/admin", cmd.AdminPanel, b.AdminOnly)
+	b.bot.Handle("/user", cmd.User, b.AdminOnly)
 	b.bot.Handle("/remind", cmd.Remind, b.AdminOnly)
 	b.bot.Handle("/closeregistration", cmd.CloseRegistration, b.AdminOnly)
 	b.bot.Handle("/openregistration", cmd.OpenRegistration, b.AdminOnly)
-	b.bot.Handle("/testimages", cmd.TestImages, b.AdminOnly)
+	b.bot.Handle("/places", cmd.PlaceCatalog, b.AdminOnly)
+	b.bot.Handle("/testimages", cmd.PlaceCatalog, b.AdminOnly)
+	b.bot.Handle("/addplace", cmd.AddPlace, b.AdminOnly)
 	b.bot.Handle("/requestfeedback", cmd.RequestFeedback, b.AdminOnly)
+	b.bot.Handle("/relaylog", cmd.RelayLog, b.AdminOnly)
+	b.bot.Handle("/reports", cmd.Reports, b.AdminOnly)
+	b.bot.Handle("/unban", cmd.Unban, b.AdminOnly)
+	b.bot.Handle("/broadcast", cmd.Broadcast, b.AdminOnly)
+	b.bot.Handle("/jobs", cmd.Jobs, b.AdminOnly)
 
 	b.bot.Handle(&btnSexMale, cb.Sex, b.RegistrationGuard)
 	b.bot.Handle(&btnSexFemale, cb.Sex, b.RegistrationGuard)
 	b.bot.Handle(&btnTime, cb.Time, b.RegistrationGuard)
 	b.bot.Handle(&btnConfirmTime, cb.ConfirmTime, b.RegistrationGuard)
 	b.bot.Handle(&btnResubmit, cb.Resubmit, b.RegistrationGuard)
+	b.bot.Handle(&btnProfileEdit, cb.ProfileEdit, b.RegistrationGuard)
 	b.bot.Handle(&btnConfirmMeeting, cb.ConfirmMeeting)
 	b.bot.Handle(&btnCancelMeeting, cb.CancelMeeting)
+	b.bot.Handle(&btnProposeTime, cb.ProposeTime)
+	b.bot.Handle(&btnProposeSlot, cb.ProposeSlot)
+	b.bot.Handle(&btnKeepTime, cb.KeepTime)
+	b.bot.Handle(&btnAcceptTime, cb.AcceptTime)
+	b.bot.Handle(&btnDeclineTime, cb.DeclineTime)
+	b.bot.Handle(&btnSeeAgain, cb.SeeAgain)
+	b.bot.Handle(&btnStartChat, cb.StartChat)
+	b.bot.Handle(&btnLeaveChat, cb.LeaveChat)
+	b.bot.Handle(&btnRate, cb.Rate)
+	b.bot.Handle(&btnRateTag, cb.RateTag)
+	b.bot.Handle(&btnNotificationToggle, cb.NotificationToggle)
+	b.bot.Handle(&btnRateDone, cb.RateDone)
+	b.bot.Handle(&btnRateSkip, cb.RateSkip)
+	b.bot.Handle(&btnReport, cb.Report)
+	b.bot.Handle(&btnReportReason, cb.ReportReason)
+	b.bot.Handle(&btnReportCancel, cb.ReportCancel)
+	b.bot.Handle(&btnBlock, cb.Block)
+	b.bot.Handle(&btnHelp, cb.Help)
+	b.bot.Handle(&btnIncidentAck, cb.AcknowledgeIncident)
 	b.bot.Handle(&btnCancelSupport, cb.CancelSupport)
 	b.bot.Handle(&btnHowItWorks, cb.HowItWorks)
 	b.bot.Handle(&btnArrivedMeeting, cb.ArrivedAtMeeting)
 	b.bot.Handle(&btnCantFindPartner, cb.CantFindPartner)
 	b.bot.Handle(&btnOptOut, cb.OptOut)
+	b.bot.Handle(&btnProfileParticipation, cb.ProfileParticipation)
 	b.bot.Handle(&btnRefreshAdmin, cb.RefreshAdmin, b.AdminOnly)
+	b.bot.Handle(&btnPlacesPage, cb.PlacesPage, b.AdminOnly)
+	b.bot.Handle(&btnPlaceEdit, cb.EditPlace, b.AdminOnly)
+	b.bot.Handle(&btnPlaceToggle, cb.TogglePlace, b.AdminOnly)
+	b.bot.Handle(&btnCancelPlaceEdit, cb.CancelPlaceEdit, b.AdminOnly)
+	b.bot.Handle(&btnReportResolve, cb.ResolveReport, b.AdminOnly)
+	b.bot.Handle(&btnBroadcastSegment, cb.BroadcastSegment, b.AdminOnly)
+	b.bot.Handle(&btnBroadcastSend, cb.BroadcastSend, b.AdminOnly)
+	b.bot.Handle(&btnBroadcastSchedule, cb.BroadcastSchedule, b.AdminOnly)
+	b.bot.Handle(&btnBroadcastCancel, cb.BroadcastCancel, b.AdminOnly)
 
 	b.bot.Handle(tele.OnText, msg.Text, b.RegistrationGuard)
 	b.bot.Handle(tele.OnSticker, msg.Sticker, b.AdminOnly)
+	b.bot.Handle(tele.OnPhoto, msg.Photo, b.AdminOnly)
+	b.bot.Handle(tele.OnLocation, msg.Location, b.RegistrationGuard)
+	b.bot.Handle(tele.OnEdited, msg.EditedLocation)
 }
 
 func (b *Bot) Start(ctx context.Context) {
@@ -172,3 +294,15 @@ func (b *Bot) Stop() {
 func (b *Bot) TeleBot() *tele.Bot {
 	return b.bot
 }
+
+func (b *Bot) Photos() *placephoto.Sender {
+	return b.photos
+}
+
+func (b *Bot) Outbox() *outbox.Sender {
+	return b.outbox
+}
+
+func (b *Bot) Invites() *invite.Sender {
+	return b.invites
+}
@@ -0,0 +1,132 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+// BroadcastSegment sets the audience of the broadcast and shows its preview with the recipient count.
+func (h *Handler) BroadcastSegment(c tele.Context) error {
+	ctx := context.Background()
+
+	args := c.Args()
+	if len(args) != 2 {
+		slog.Error("invalid broadcast segment data", "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	broadcastID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse broadcast id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	broadcast, count, err := h.Broadcasts.ChooseSegment(ctx, broadcastID, domain.BroadcastSegment(args[1]))
+	if err != nil {
+		if errors.Is(err, usecase.ErrBroadcastNotDraft) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Broadcast.AlreadyProcessed})
+		}
+		slog.Error("choose broadcast segment", sl.Err(err), "broadcast_id", broadcastID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	_ = c.Delete()
+
+	source := &tele.Message{ID: broadcast.SourceMessageID, Chat: &tele.Chat{ID: broadcast.SourceChatID}}
+	if _, err := h.Bot.Copy(c.Recipient(), source); err != nil {
+		slog.Error("copy broadcast preview", sl.Err(err), "broadcast_id", broadcastID)
+		return c.Send(messages.M.Broadcast.PreviewFailed)
+	}
+
+	return c.Send(h.Broadcasts.FormatPreview(broadcast, count), view.BroadcastConfirmKeyboard(args[0]))
+}
+
+// BroadcastSend queues the broadcast right away and posts its live progress.
+func (h *Handler) BroadcastSend(c tele.Context) error {
+	ctx := context.Background()
+
+	broadcastID, err := strconv.ParseInt(c.Callback().Data, 10, 64)
+	if err != nil {
+		slog.Error("parse broadcast id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	broadcast, err := h.Broadcasts.Send(ctx, broadcastID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrNoRecipients):
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Broadcast.NoRecipients})
+		case errors.Is(err, usecase.ErrBroadcastNotDraft):
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Broadcast.AlreadyProcessed})
+		default:
+			slog.Error("send broadcast", sl.Err(err), "broadcast_id", broadcastID)
+			return c.Respond()
+		}
+	}
+
+	slog.Info("broadcast started", "broadcast_id", broadcast.ID, "segment", broadcast.Segment, "total", broadcast.Total)
+
+	_ = c.Respond()
+	_ = c.Edit(messages.Format(messages.M.Broadcast.Started, map[string]string{"id": fmt.Sprintf("%d", broadcast.ID)}))
+
+	content := h.Broadcasts.FormatProgress(broadcast, domain.OutboxStats{Pending: uint(broadcast.Total)})
+	msg, err := h.Bot.Send(c.Recipient(), content)
+	if err != nil {
+		slog.Error("send broadcast progress", sl.Err(err), "broadcast_id", broadcast.ID)
+		return nil
+	}
+
+	if err := h.Broadcasts.SetProgressMessage(ctx, broadcast.ID, msg.ID); err != nil {
+		slog.Error("store broadcast progress message", sl.Err(err), "broadcast_id", broadcast.ID)
+	}
+	return nil
+}
+
+func (h *Handler) BroadcastSchedule(c tele.Context) error {
+	broadcastID, err := strconv.ParseInt(c.Callback().Data, 10, 64)
+	if err != nil {
+		slog.Error("parse broadcast id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	if err := h.Broadcasts.AskTime(context.Background(), c.Sender().ID, broadcastID); err != nil {
+		if errors.Is(err, usecase.ErrNoDraft) || errors.Is(err, usecase.ErrBroadcastNotDraft) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Broadcast.AlreadyProcessed})
+		}
+		slog.Error("ask broadcast time", sl.Err(err), "broadcast_id", broadcastID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Send(messages.M.Broadcast.AskTime, view.CancelBroadcastKeyboard(c.Callback().Data))
+}
+
+func (h *Handler) BroadcastCancel(c tele.Context) error {
+	broadcastID, err := strconv.ParseInt(c.Callback().Data, 10, 64)
+	if err != nil {
+		slog.Error("parse broadcast id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	if err := h.Broadcasts.Cancel(context.Background(), c.Sender().ID, broadcastID); err != nil {
+		if errors.Is(err, usecase.ErrBroadcastNotDraft) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Broadcast.AlreadyProcessed})
+		}
+		slog.Error("cancel broadcast", sl.Err(err), "broadcast_id", broadcastID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(messages.M.Broadcast.Cancelled)
+}
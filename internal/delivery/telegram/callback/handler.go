@@ -1,8 +1,9 @@
 package callback
 
 import (
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/invite"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/placephoto"
 	"github.com/jus1d/kypidbot/internal/domain"
-	"github.com/jus1d/kypidbot/internal/infrastructure/s3"
 	"github.com/jus1d/kypidbot/internal/usecase"
 	tele "gopkg.in/telebot.v3"
 )
@@ -11,10 +12,18 @@ type Handler struct {
 	Registration *usecase.Registration
 	Admin        *usecase.Admin
 	Meeting      *usecase.Meeting
+	Places       *usecase.Places
+	Relay        *usecase.Relay
+	Rating       *usecase.Rating
+	Moderation   *usecase.Moderation
+	Broadcasts   *usecase.Broadcasts
+	Preferences  *usecase.Preferences
+	Safety       *usecase.Safety
 	Users        domain.UserRepository
 	UserMessages domain.UserMessageRepository
 	Bot          *tele.Bot
-	S3           *s3.Client
+	Photos       *placephoto.Sender
+	Invites      *invite.Sender
 }
 
 func (h *Handler) DeleteAndSend(c tele.Context, what any, opts ...any) error {
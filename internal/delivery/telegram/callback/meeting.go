@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/invite"
 	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
 	"github.com/jus1d/kypidbot/internal/domain"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
@@ -25,6 +26,9 @@ func (h *Handler) ConfirmMeeting(c tele.Context) error {
 
 	both, meeting, err := h.Meeting.ConfirmMeeting(context.Background(), meetingID, telegramID)
 	if err != nil {
+		if domain.IsTransitionError(err) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Meeting.Status.Outdated})
+		}
 		slog.Error("confirm meeting", sl.Err(err))
 		return c.Respond()
 	}
@@ -49,8 +53,8 @@ func (h *Handler) ConfirmMeeting(c tele.Context) error {
 		}
 
 		place, err := h.Meeting.GetPlace(context.Background(), *meeting.PlaceID)
-		if err != nil {
-			slog.Error("get place", sl.Err(err))
+		if err != nil || place == nil {
+			slog.Error("get place", sl.Err(err), "place_id", *meeting.PlaceID)
 			return nil
 		}
 
@@ -101,24 +105,10 @@ func (h *Handler) ConfirmMeeting(c tele.Context) error {
 
 		_ = c.Delete()
 
-		if place.PhotoURL != "" {
-			reader, err := h.S3.GetPhoto(context.Background(), place.PhotoURL)
-			if err != nil {
-				slog.Error("get photo from s3", sl.Err(err))
-				if err := c.Send(finalMessage, cancelkb); err != nil {
-					slog.Error("send both confirmed to user", sl.Err(err))
-				}
-			} else {
-				defer reader.Close()
-				photo := &tele.Photo{File: tele.FromReader(reader), Caption: finalMessage}
-				if err := c.Send(photo, cancelkb); err != nil {
-					slog.Error("send photo to user", sl.Err(err))
-				}
-			}
+		if msg, err := h.Photos.Send(context.Background(), c.Recipient(), place, finalMessage, cancelkb); err != nil {
+			slog.Error("send both confirmed to user", sl.Err(err))
 		} else {
-			if err := c.Send(finalMessage, cancelkb); err != nil {
-				slog.Error("send both confirmed to user", sl.Err(err))
-			}
+			_ = h.UserMessages.StoreMessageID(context.Background(), meetingID, telegramID, invite.MessageKey, msg.ID)
 		}
 
 		partnerNotifID, _ := h.UserMessages.GetMessageID(context.Background(), meetingID, telegramID, "partner_msg")
@@ -132,26 +122,22 @@ func (h *Handler) ConfirmMeeting(c tele.Context) error {
 				_ = h.Bot.Delete(&tele.Message{Chat: &tele.Chat{ID: partnerID}, ID: partnerOriginalID})
 			}
 
-			if place.PhotoURL != "" {
-				reader, err := h.S3.GetPhoto(context.Background(), place.PhotoURL)
-				if err != nil {
-					slog.Error("get photo from s3 for partner", sl.Err(err))
-					if _, err := h.Bot.Send(&tele.User{ID: partnerID}, finalMessage, cancelkb); err != nil {
-						slog.Error("send both confirmed to partner", sl.Err(err))
-					}
-				} else {
-					defer reader.Close()
-					photo := &tele.Photo{File: tele.FromReader(reader), Caption: finalMessage}
-					if _, err := h.Bot.Send(&tele.User{ID: partnerID}, photo, cancelkb); err != nil {
-						slog.Error("send photo to partner", sl.Err(err))
-					}
-				}
+			if msg, err := h.Photos.Send(context.Background(), &tele.User{ID: partnerID}, place, finalMessage, cancelkb); err != nil {
+				slog.Error("send both confirmed to partner", sl.Err(err))
 			} else {
-				if _, err := h.Bot.Send(&tele.User{ID: partnerID}, finalMessage, cancelkb); err != nil {
-					slog.Error("send both confirmed to partner", sl.Err(err))
-				}
+				_ = h.UserMessages.StoreMessageID(context.Background(), meetingID, partnerID, invite.MessageKey, msg.ID)
 			}
 		}
+
+		calendar, err := h.Meeting.Calendar(context.Background(), meeting, false)
+		if err != nil {
+			slog.Error("make meeting calendar", sl.Err(err), "meeting_id", meetingID)
+			return nil
+		}
+		h.Invites.SendCalendar(telegramID, calendar, messages.M.Meeting.Calendar.Caption)
+		if partnerID != 0 {
+			h.Invites.SendCalendar(partnerID, calendar, messages.M.Meeting.Calendar.Caption)
+		}
 	}
 
 	return nil
@@ -165,12 +151,27 @@ func (h *Handler) ArrivedAtMeeting(c tele.Context) error {
 		return c.Respond()
 	}
 
+	telegramID := c.Sender().ID
+
+	verify, err := h.Meeting.StartCheckIn(context.Background(), meetingID, telegramID)
+	if err != nil {
+		if domain.IsTransitionError(err) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Meeting.Status.Outdated})
+		}
+		slog.Error("start check-in", sl.Err(err), "meeting_id", meetingID)
+	}
+
 	_ = c.Respond()
 
-	telegramID := c.Sender().ID
+	if verify {
+		_ = c.Delete()
+		return c.Send(messages.M.Notifications.CheckIn.Ask, view.LocationKeyboard())
+	}
 
 	if err := h.Meeting.SetArrived(context.Background(), meetingID, telegramID); err != nil {
-		slog.Error("set arrived state", sl.Err(err))
+		if !domain.IsTransitionError(err) {
+			slog.Error("set arrived state", sl.Err(err))
+		}
 		return nil
 	}
 
@@ -237,17 +238,28 @@ func (h *Handler) CancelMeeting(c tele.Context) error {
 
 	telegramID := c.Sender().ID
 
-	ok, err := h.Meeting.CancelMeeting(context.Background(), meetingID, telegramID)
+	meeting, wasConfirmed, err := h.Meeting.CancelMeeting(context.Background(), meetingID, telegramID)
 	if err != nil {
+		if domain.IsTransitionError(err) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Meeting.Status.Outdated})
+		}
 		slog.Error("cancel meeting", sl.Err(err))
 		return c.Respond()
 	}
-	if !ok {
+	if meeting == nil {
 		return c.Respond()
 	}
 
 	partner, _ := h.Meeting.GetPartner(context.Background(), meetingID, telegramID)
 
+	var calendar []byte
+	if wasConfirmed {
+		calendar, err = h.Meeting.Calendar(context.Background(), meeting, true)
+		if err != nil {
+			slog.Error("make meeting calendar cancellation", sl.Err(err), "meeting_id", meetingID)
+		}
+	}
+
 	partnerMention := "unknown"
 	if partner != nil {
 		partnerMention = messages.Mention(partner.TelegramID, partner.FirstName, partner.Username)
@@ -258,6 +270,9 @@ func (h *Handler) CancelMeeting(c tele.Context) error {
 	})); err != nil {
 		slog.Error("send cancelled message", sl.Err(err))
 	}
+	if calendar != nil {
+		h.Invites.SendCalendar(telegramID, calendar, messages.M.Meeting.Calendar.CancelCaption)
+	}
 
 	if partner != nil {
 		user, _ := h.Users.GetUser(context.Background(), telegramID)
@@ -272,7 +287,23 @@ func (h *Handler) CancelMeeting(c tele.Context) error {
 		if err != nil {
 			slog.Error("send partner cancelled", sl.Err(err), "partner_id", partner.TelegramID)
 		}
+		if calendar != nil {
+			h.Invites.SendCalendar(partner.TelegramID, calendar, messages.M.Meeting.Calendar.CancelCaption)
+		}
 	}
 
 	return nil
 }
+
+// sendCalendarCancel sends both participants a calendar file that removes the
+// meeting's event from their calendars.
+func (h *Handler) sendCalendarCancel(ctx context.Context, meeting *domain.Meeting, caption string) {
+	calendar, err := h.Meeting.Calendar(ctx, meeting, true)
+	if err != nil {
+		slog.Error("make meeting calendar cancellation", sl.Err(err), "meeting_id", meeting.ID)
+		return
+	}
+	for _, id := range []int64{meeting.DillID, meeting.DoeID} {
+		h.Invites.SendCalendar(id, calendar, caption)
+	}
+}
@@ -0,0 +1,184 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) Report(c tele.Context) error {
+	_ = c.Respond()
+	return c.Send(messages.M.Moderation.Report.Choose, view.ReportReasonsKeyboard(c.Callback().Data))
+}
+
+func (h *Handler) ReportReason(c tele.Context) error {
+	ctx := context.Background()
+
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+
+	meetingID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", args[0])
+		return c.Respond()
+	}
+
+	report, err := h.Moderation.Report(ctx, meetingID, c.Sender().ID, domain.ReportReason(args[1]))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrAlreadyReported):
+			_ = c.Respond()
+			return c.Edit(messages.M.Moderation.Report.Already)
+		case errors.Is(err, usecase.ErrNotParticipant):
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Meeting.Status.Outdated})
+		default:
+			slog.Error("report partner", sl.Err(err), "meeting_id", meetingID)
+			return c.Respond()
+		}
+	}
+
+	_ = c.Respond()
+	if err := c.Edit(messages.M.Moderation.Report.Sent); err != nil {
+		slog.Error("edit report message", sl.Err(err))
+	}
+
+	h.notifyAdminsOfReport(ctx, report)
+	return nil
+}
+
+func (h *Handler) ReportCancel(c tele.Context) error {
+	_ = c.Respond()
+	return c.Edit(messages.M.Moderation.Report.Cancelled)
+}
+
+func (h *Handler) Block(c tele.Context) error {
+	meetingID, err := strconv.ParseInt(c.Callback().Data, 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	partner, err := h.Moderation.Block(context.Background(), meetingID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotParticipant) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Meeting.Status.Outdated})
+		}
+		slog.Error("block partner", sl.Err(err), "meeting_id", meetingID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+
+	mention := ""
+	if partner != nil {
+		mention = messages.Mention(partner.TelegramID, partner.FirstName, partner.Username)
+	}
+	return c.Send(messages.Format(messages.M.Moderation.Blocked, map[string]string{
+		"partner_mention": mention,
+	}))
+}
+
+func (h *Handler) ResolveReport(c tele.Context) error {
+	ctx := context.Background()
+
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+
+	reportID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse report id", sl.Err(err), "data", args[0])
+		return c.Respond()
+	}
+
+	report, cancelled, err := h.Moderation.Resolve(ctx, reportID, c.Sender().ID, domain.ReportStatus(args[1]))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrReportResolved), errors.Is(err, usecase.ErrReportNotFound):
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Moderation.Queue.AlreadyResolved})
+		case errors.Is(err, usecase.ErrCannotBanAdmin):
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Moderation.Queue.CannotBanAdmin})
+		default:
+			slog.Error("resolve report", sl.Err(err), "report_id", reportID)
+			return c.Respond()
+		}
+	}
+
+	_ = c.Respond()
+
+	switch report.Status {
+	case domain.ReportStatusWarned:
+		if _, err := h.Bot.Send(&tele.User{ID: report.ReportedID}, messages.M.Moderation.Warning); err != nil {
+			slog.Error("send warning", sl.Err(err), "telegram_id", report.ReportedID)
+		}
+	case domain.ReportStatusBanned:
+		if _, err := h.Bot.Send(&tele.User{ID: report.ReportedID}, messages.M.Moderation.Banned); err != nil {
+			slog.Error("send ban notice", sl.Err(err), "telegram_id", report.ReportedID)
+		}
+		for _, meeting := range cancelled {
+			partnerID := meeting.Partner(report.ReportedID)
+			if _, err := h.Bot.Send(&tele.User{ID: partnerID}, messages.M.Moderation.PartnerRemoved); err != nil {
+				slog.Error("send partner removed", sl.Err(err), "telegram_id", partnerID)
+			}
+			if meeting.Confirmed() {
+				h.sendCalendarCancel(ctx, &meeting, messages.M.Meeting.Calendar.CancelCaption)
+			}
+		}
+	}
+
+	resolved := messages.Format(messages.M.Moderation.Queue.Resolved, map[string]string{
+		"id":     fmt.Sprintf("%d", report.ID),
+		"status": messages.M.Moderation.Statuses[string(report.Status)],
+	})
+	if err := c.Edit(resolved); err != nil {
+		slog.Error("edit report card", sl.Err(err))
+	}
+
+	next, pending, err := h.Moderation.NextPending(ctx)
+	if err != nil {
+		slog.Error("get next report", sl.Err(err))
+		return nil
+	}
+	if next == nil {
+		return c.Send(messages.M.Moderation.Queue.Empty)
+	}
+
+	content, err := h.Moderation.FormatReport(ctx, next, pending)
+	if err != nil {
+		slog.Error("format report", sl.Err(err), "report_id", next.ID)
+		return nil
+	}
+	return c.Send(content, view.ReportReviewKeyboard(fmt.Sprintf("%d", next.ID)))
+}
+
+func (h *Handler) notifyAdminsOfReport(ctx context.Context, report *domain.Report) {
+	admins, err := h.Users.GetAdmins(ctx)
+	if err != nil {
+		slog.Error("get admins", sl.Err(err))
+		return
+	}
+
+	content, err := h.Moderation.FormatAdminNotice(ctx, report)
+	if err != nil {
+		slog.Error("format report notice", sl.Err(err), "report_id", report.ID)
+		return
+	}
+
+	for _, admin := range admins {
+		if _, err := h.Bot.Send(&tele.User{ID: admin.TelegramID}, content); err != nil {
+			slog.Error("send report to admin", sl.Err(err), "admin_id", admin.TelegramID)
+		}
+	}
+}
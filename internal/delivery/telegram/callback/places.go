@@ -0,0 +1,123 @@
+package callback
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) PlacesPage(c tele.Context) error {
+	ctx := context.Background()
+
+	page, err := strconv.Atoi(c.Callback().Data)
+	if err != nil {
+		slog.Error("parse places page", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	place, page, total, err := h.Places.CatalogPage(ctx, page)
+	if err != nil {
+		slog.Error("get place catalog", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	_ = c.Delete()
+
+	_, err = h.Photos.Send(ctx, c.Recipient(), place, h.Places.FormatCard(place), view.PlaceCatalogKeyboard(place, page, total))
+	return err
+}
+
+func (h *Handler) TogglePlace(c tele.Context) error {
+	ctx := context.Background()
+
+	placeID, err := strconv.ParseInt(c.Callback().Data, 10, 64)
+	if err != nil {
+		slog.Error("parse place id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	if _, err := h.Places.ToggleActive(ctx, placeID); err != nil {
+		slog.Error("toggle place", sl.Err(err), "place_id", placeID)
+		return c.Respond()
+	}
+
+	place, page, total, err := h.Places.CatalogPageOf(ctx, placeID)
+	if err != nil {
+		slog.Error("get place catalog", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+
+	content := h.Places.FormatCard(place)
+	kb := view.PlaceCatalogKeyboard(place, page, total)
+	if c.Message().Photo != nil {
+		_, err = h.Bot.EditCaption(c.Message(), content, kb)
+	} else {
+		_, err = h.Bot.Edit(c.Message(), content, kb)
+	}
+	if err != nil {
+		slog.Error("edit place card", sl.Err(err))
+	}
+	return nil
+}
+
+func (h *Handler) EditPlace(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		slog.Error("invalid place edit data", "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	placeID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse place id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	field := domain.PlaceField(args[1])
+
+	var prompt string
+	switch field {
+	case domain.PlaceFieldDescription:
+		prompt = messages.M.Admin.Places.Ask.Description
+	case domain.PlaceFieldRoute:
+		prompt = messages.M.Admin.Places.Ask.Route
+	case domain.PlaceFieldQuality:
+		prompt = messages.M.Admin.Places.Ask.Quality
+	case domain.PlaceFieldPhoto:
+		prompt = messages.M.Admin.Places.Ask.Photo
+	case domain.PlaceFieldLocation:
+		prompt = messages.M.Admin.Places.Ask.Location
+	case domain.PlaceFieldRadius:
+		prompt = messages.M.Admin.Places.Ask.Radius
+	default:
+		slog.Error("unknown place field", "field", field)
+		return c.Respond()
+	}
+
+	if err := h.Places.StartEdit(context.Background(), c.Sender().ID, placeID, field); err != nil {
+		slog.Error("start place edit", sl.Err(err), "place_id", placeID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Send(prompt, view.CancelPlaceEditKeyboard())
+}
+
+func (h *Handler) CancelPlaceEdit(c tele.Context) error {
+	if err := h.Places.CancelEdit(context.Background(), c.Sender().ID); err != nil {
+		slog.Error("cancel place edit", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(messages.M.Admin.Places.EditCancelled)
+}
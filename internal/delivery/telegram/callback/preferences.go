@@ -0,0 +1,28 @@
+package callback
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) NotificationToggle(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return c.Respond()
+	}
+
+	muted, err := h.Preferences.Toggle(context.Background(), c.Sender().ID, domain.NotificationCategory(args[0]))
+	if err != nil {
+		slog.Error("toggle notification category", sl.Err(err), "category", args[0])
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(messages.M.Preferences.Title, view.PreferencesKeyboard(muted))
+}
@@ -0,0 +1,89 @@
+package callback
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+// ProfileEdit jumps straight to the registration step of a single profile
+// field. Once it is done, the user gets back to their profile.
+func (h *Handler) ProfileEdit(c tele.Context) error {
+	sender := c.Sender()
+
+	args := c.Args()
+	if len(args) != 1 {
+		return c.Respond()
+	}
+
+	var (
+		state domain.UserState
+		what  string
+		kb    *tele.ReplyMarkup
+	)
+	switch args[0] {
+	case "sex":
+		state, what, kb = domain.UserStateAwaitingSex, messages.M.Profile.Sex.AskRetry, view.SexKeyboard()
+	case "about":
+		state, what = domain.UserStateAwaitingAbout, messages.M.Profile.About.Request
+	case "time":
+		binaryStr, err := h.Registration.GetTimeRanges(context.Background(), sender.ID)
+		if err != nil {
+			slog.Error("get time ranges", sl.Err(err))
+			return c.Respond()
+		}
+		state, what, kb = domain.UserStateAwaitingTime, messages.M.Profile.View.AskTime, view.TimeKeyboard(domain.BinaryToSet(binaryStr))
+	default:
+		return c.Respond()
+	}
+
+	if err := h.Registration.BeginEdit(context.Background(), sender.ID, state); err != nil {
+		slog.Error("begin edit", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	if kb == nil {
+		return c.Send(what)
+	}
+	return c.Send(what, kb)
+}
+
+func (h *Handler) ProfileParticipation(c tele.Context) error {
+	sender := c.Sender()
+
+	user, err := h.Users.GetUser(context.Background(), sender.ID)
+	if err != nil || user == nil {
+		slog.Error("get user for participation", sl.Err(err))
+		return c.Respond()
+	}
+
+	if !user.IsRegistered {
+		return c.Respond()
+	}
+
+	user.OptedOut = !user.OptedOut
+	if err := h.Users.SetOptedOut(context.Background(), sender.ID, user.OptedOut); err != nil {
+		slog.Error("set opted out", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(h.Registration.FormatProfile(user), view.ProfileKeyboard(user))
+}
+
+// sendProfile sends the user's profile after they have edited a field of it.
+func (h *Handler) sendProfile(c tele.Context) error {
+	user, err := h.Registration.GetUser(context.Background(), c.Sender().ID)
+	if err != nil || user == nil {
+		slog.Error("get user", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(h.Registration.FormatProfile(user), view.ProfileKeyboard(user))
+}
@@ -0,0 +1,170 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) ProposeTime(c tele.Context) error {
+	data := c.Callback().Data
+	meetingID, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", data)
+		return c.Respond()
+	}
+
+	slots, err := h.Meeting.ProposalSlots(context.Background(), meetingID, c.Sender().ID)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrMeetingUnavailable) {
+			slog.Error("get proposal slots", sl.Err(err), "meeting_id", meetingID)
+		}
+		return c.Respond()
+	}
+
+	if len(slots) == 0 {
+		return c.Respond(&tele.CallbackResponse{Text: messages.M.Meeting.Proposal.NoSlots, ShowAlert: true})
+	}
+
+	_ = c.Respond()
+	return c.Send(messages.M.Meeting.Proposal.Choose, view.ProposalSlotsKeyboard(data, slots))
+}
+
+func (h *Handler) ProposeSlot(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+
+	meetingID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", args[0])
+		return c.Respond()
+	}
+
+	unix, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		slog.Error("parse proposal time", sl.Err(err), "data", args[1])
+		return c.Respond()
+	}
+
+	proposal, partnerID, err := h.Meeting.ProposeTime(context.Background(), meetingID, c.Sender().ID, time.Unix(unix, 0))
+	if err != nil {
+		if errors.Is(err, usecase.ErrMeetingUnavailable) || errors.Is(err, usecase.ErrInvalidSlot) {
+			_ = c.Respond()
+			return c.Edit(messages.M.Meeting.Proposal.Outdated)
+		}
+		slog.Error("propose time", sl.Err(err), "meeting_id", meetingID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+
+	t := map[string]string{"time": domain.Timef(proposal.Time)}
+
+	if partnerID != 0 {
+		_, err := h.Bot.Send(&tele.User{ID: partnerID},
+			messages.Format(messages.M.Meeting.Proposal.Offer, t),
+			view.ProposalKeyboard(fmt.Sprintf("%d", proposal.ID)))
+		if err != nil {
+			slog.Error("send time proposal", sl.Err(err), "partner_id", partnerID)
+		}
+	}
+
+	return c.Edit(messages.Format(messages.M.Meeting.Proposal.Sent, t))
+}
+
+func (h *Handler) KeepTime(c tele.Context) error {
+	_ = c.Respond()
+	return c.Delete()
+}
+
+func (h *Handler) AcceptTime(c tele.Context) error {
+	data := c.Callback().Data
+	proposalID, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		slog.Error("parse proposal id", sl.Err(err), "data", data)
+		return c.Respond()
+	}
+
+	ctx := context.Background()
+
+	result, err := h.Meeting.AcceptProposal(ctx, proposalID, c.Sender().ID)
+	switch {
+	case errors.Is(err, usecase.ErrNoFreePlace):
+		_ = c.Respond()
+
+		content := messages.Format(messages.M.Meeting.Proposal.NoFreePlace, map[string]string{
+			"time": domain.Timef(result.Proposal.Time),
+		})
+		if _, err := h.Bot.Send(&tele.User{ID: result.Proposal.ProposerID}, content); err != nil {
+			slog.Error("send no free place", sl.Err(err), "telegram_id", result.Proposal.ProposerID)
+		}
+		return c.Edit(content)
+	case errors.Is(err, usecase.ErrMeetingUnavailable) || errors.Is(err, usecase.ErrProposalResolved):
+		_ = c.Respond()
+		return c.Edit(messages.M.Meeting.Proposal.Outdated)
+	case err != nil:
+		slog.Error("accept proposal", sl.Err(err), "proposal_id", proposalID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	_ = c.Delete()
+
+	h.Invites.Update(ctx, result.Meeting, result.PlaceChanged)
+	if result.Previous.Confirmed() {
+		h.sendCalendarCancel(ctx, result.Previous, messages.M.Meeting.Calendar.RescheduleCaption)
+	}
+
+	content := messages.Format(messages.M.Meeting.Proposal.Accepted, map[string]string{
+		"time": domain.Timef(result.Meeting.Time),
+	})
+	for _, id := range []int64{result.Meeting.DillID, result.Meeting.DoeID} {
+		if _, err := h.Bot.Send(&tele.User{ID: id}, content); err != nil {
+			slog.Error("send proposal accepted", sl.Err(err), "telegram_id", id)
+		}
+	}
+
+	return nil
+}
+
+func (h *Handler) DeclineTime(c tele.Context) error {
+	data := c.Callback().Data
+	proposalID, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		slog.Error("parse proposal id", sl.Err(err), "data", data)
+		return c.Respond()
+	}
+
+	proposal, err := h.Meeting.DeclineProposal(context.Background(), proposalID, c.Sender().ID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrMeetingUnavailable) || errors.Is(err, usecase.ErrProposalResolved) {
+			_ = c.Respond()
+			return c.Edit(messages.M.Meeting.Proposal.Outdated)
+		}
+		slog.Error("decline proposal", sl.Err(err), "proposal_id", proposalID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+
+	_, err = h.Bot.Send(&tele.User{ID: proposal.ProposerID}, messages.Format(messages.M.Meeting.Proposal.Declined, map[string]string{
+		"time": domain.Timef(proposal.Time),
+	}))
+	if err != nil {
+		slog.Error("send proposal declined", sl.Err(err), "telegram_id", proposal.ProposerID)
+	}
+
+	return c.Edit(messages.M.Meeting.Proposal.YouDeclined)
+}
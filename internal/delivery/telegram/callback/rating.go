@@ -0,0 +1,95 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) Rate(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+
+	meetingID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", args[0])
+		return c.Respond()
+	}
+
+	stars, err := strconv.Atoi(args[1])
+	if err != nil {
+		slog.Error("parse stars", sl.Err(err), "data", args[1])
+		return c.Respond()
+	}
+
+	rating, err := h.Rating.Rate(context.Background(), meetingID, c.Sender().ID, stars)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotParticipant) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Meeting.Status.Outdated})
+		}
+		slog.Error("rate meeting", sl.Err(err), "meeting_id", meetingID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(formatRatingTags(rating), view.RatingTagsKeyboard(args[0], rating))
+}
+
+func (h *Handler) RateTag(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+
+	meetingID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", args[0])
+		return c.Respond()
+	}
+
+	rating, err := h.Rating.ToggleTag(context.Background(), meetingID, c.Sender().ID, domain.RatingTag(args[1]))
+	if err != nil {
+		slog.Error("toggle rating tag", sl.Err(err), "meeting_id", meetingID, "tag", args[1])
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(formatRatingTags(rating), view.RatingTagsKeyboard(args[0], rating))
+}
+
+func (h *Handler) RateDone(c tele.Context) error {
+	if err := h.Registration.SetState(context.Background(), c.Sender().ID, domain.UserStateAwaitingFeedback); err != nil {
+		slog.Error("set state awaiting_feedback", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(messages.M.Feedback.Request, view.SkipCommentKeyboard())
+}
+
+func (h *Handler) RateSkip(c tele.Context) error {
+	if err := h.Registration.SetState(context.Background(), c.Sender().ID, domain.UserStateCompleted); err != nil {
+		slog.Error("set state after feedback", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(messages.M.Feedback.ThankYou)
+}
+
+func formatRatingTags(rating *domain.Rating) string {
+	return messages.Format(messages.M.Rating.Tags, map[string]string{
+		"stars": strings.Repeat("⭐", rating.Stars),
+	})
+}
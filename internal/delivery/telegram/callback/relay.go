@@ -0,0 +1,44 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) StartChat(c tele.Context) error {
+	data := c.Callback().Data
+	meetingID, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", data)
+		return c.Respond()
+	}
+
+	if err := h.Relay.Start(context.Background(), meetingID, c.Sender().ID); err != nil {
+		if errors.Is(err, usecase.ErrRelayClosed) {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Relay.Closed, ShowAlert: true})
+		}
+		slog.Error("start relay", sl.Err(err), "meeting_id", meetingID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Send(messages.M.Relay.Started, view.LeaveChatKeyboard())
+}
+
+func (h *Handler) LeaveChat(c tele.Context) error {
+	if err := h.Relay.Leave(context.Background(), c.Sender().ID); err != nil {
+		slog.Error("leave relay", sl.Err(err))
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+	return c.Edit(messages.M.Relay.Left)
+}
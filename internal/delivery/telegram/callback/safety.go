@@ -0,0 +1,92 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+// Help alerts the organizers that the participant needs help at their meeting
+// and sends them the safety instructions.
+func (h *Handler) Help(c tele.Context) error {
+	ctx := context.Background()
+
+	meetingID, err := strconv.ParseInt(c.Callback().Data, 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+
+	// instructions go first, whatever happens with the alert
+	if err := c.Send(messages.M.Safety.Instructions); err != nil {
+		slog.Error("send safety instructions", sl.Err(err))
+	}
+
+	incident, err := h.Safety.Alert(ctx, meetingID, c.Sender().ID)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrNotParticipant) {
+			slog.Error("create incident", sl.Err(err), "meeting_id", meetingID)
+		}
+		return nil
+	}
+
+	slog.Warn("safety incident", slog.Int64("incident_id", incident.ID), slog.Int64("meeting_id", meetingID), slog.Int64("telegram_id", c.Sender().ID))
+
+	content, err := h.Safety.FormatAlert(ctx, incident)
+	if err != nil {
+		slog.Error("format incident alert", sl.Err(err), "incident_id", incident.ID)
+		return nil
+	}
+
+	chats, err := h.Safety.AlertChats(ctx)
+	if err != nil {
+		slog.Error("get alert chats", sl.Err(err))
+		return nil
+	}
+
+	kb := view.AcknowledgeIncidentKeyboard(fmt.Sprintf("%d", incident.ID))
+	for _, id := range chats {
+		if _, err := h.Bot.Send(tele.ChatID(id), content, kb); err != nil {
+			slog.Error("send incident alert", sl.Err(err), "chat_id", id)
+		}
+	}
+	return nil
+}
+
+func (h *Handler) AcknowledgeIncident(c tele.Context) error {
+	ctx := context.Background()
+
+	incidentID, err := strconv.ParseInt(c.Callback().Data, 10, 64)
+	if err != nil {
+		slog.Error("parse incident id", sl.Err(err), "data", c.Callback().Data)
+		return c.Respond()
+	}
+
+	incident, ok, err := h.Safety.Acknowledge(ctx, incidentID, c.Sender().ID)
+	if err != nil {
+		slog.Error("acknowledge incident", sl.Err(err), "incident_id", incidentID)
+		return c.Respond()
+	}
+	if !ok {
+		_ = c.Respond(&tele.CallbackResponse{Text: messages.M.Safety.AlreadyAcknowledged})
+	} else {
+		_ = c.Respond()
+	}
+
+	content, err := h.Safety.FormatAlert(ctx, incident)
+	if err != nil {
+		slog.Error("format incident alert", sl.Err(err), "incident_id", incidentID)
+		return nil
+	}
+	return c.Edit(content)
+}
@@ -0,0 +1,65 @@
+package callback
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) SeeAgain(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+
+	meetingID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		slog.Error("parse meeting id", sl.Err(err), "data", args[0])
+		return c.Respond()
+	}
+
+	yes := args[1] == "yes"
+	telegramID := c.Sender().ID
+
+	mutual, partner, err := h.Meeting.AnswerSeeAgain(context.Background(), meetingID, telegramID, yes)
+	if err != nil {
+		slog.Error("answer see again", sl.Err(err), "meeting_id", meetingID)
+		return c.Respond()
+	}
+
+	_ = c.Respond()
+
+	noted := messages.M.Notifications.SeeAgain.NoNoted
+	if yes {
+		noted = messages.M.Notifications.SeeAgain.YesNoted
+	}
+	if err := c.Edit(noted); err != nil {
+		slog.Error("edit see again message", sl.Err(err))
+	}
+
+	if !mutual {
+		return nil
+	}
+
+	if err := c.Send(messages.Format(messages.M.Notifications.SeeAgain.Mutual, map[string]string{
+		"partner_mention": messages.Mention(partner.TelegramID, partner.FirstName, partner.Username),
+	})); err != nil {
+		slog.Error("send mutual see again to user", sl.Err(err))
+	}
+
+	user, _ := h.Users.GetUser(context.Background(), telegramID)
+	if user != nil {
+		_, err := h.Bot.Send(&tele.User{ID: partner.TelegramID}, messages.Format(messages.M.Notifications.SeeAgain.Mutual, map[string]string{
+			"partner_mention": messages.Mention(user.TelegramID, user.FirstName, user.Username),
+		}))
+		if err != nil {
+			slog.Error("send mutual see again to partner", sl.Err(err), "partner_id", partner.TelegramID)
+		}
+	}
+
+	return nil
+}
@@ -27,15 +27,27 @@ func (h *Handler) Sex(c tele.Context) error {
 		return c.Respond()
 	}
 
-	if err := h.Registration.SetState(context.Background(), sender.ID, domain.UserStateAwaitingAbout); err != nil {
-		slog.Error("set state", sl.Err(err))
+	editing, err := h.Registration.FinishEdit(context.Background(), sender.ID)
+	if err != nil {
+		slog.Error("finish edit", sl.Err(err))
 		return c.Respond()
 	}
 
+	if !editing {
+		if err := h.Registration.SetState(context.Background(), sender.ID, domain.UserStateAwaitingAbout); err != nil {
+			slog.Error("set state", sl.Err(err))
+			return c.Respond()
+		}
+	}
+
 	content := fmt.Sprintf("%s\n\n%s %s", c.Message().Text, messages.M.UI.Chosen, sexLabel)
 	if _, err := h.Bot.Edit(c.Message(), content); err != nil {
 		slog.Error("edit sex message", sl.Err(err))
 	}
 
+	if editing {
+		return h.sendProfile(c)
+	}
+
 	return c.Send(messages.M.Profile.About.Request)
 }
@@ -14,11 +14,19 @@ import (
 func (h *Handler) ConfirmTime(c tele.Context) error {
 	sender := c.Sender()
 
-	if err := h.Registration.SetState(context.Background(), sender.ID, domain.UserStateCompleted); err != nil {
-		slog.Error("set state", sl.Err(err))
+	editing, err := h.Registration.FinishEdit(context.Background(), sender.ID)
+	if err != nil {
+		slog.Error("finish edit", sl.Err(err))
 		return c.Respond()
 	}
 
+	if !editing {
+		if err := h.Registration.SetState(context.Background(), sender.ID, domain.UserStateCompleted); err != nil {
+			slog.Error("set state", sl.Err(err))
+			return c.Respond()
+		}
+	}
+
 	binaryStr, err := h.Registration.GetTimeRanges(context.Background(), sender.ID)
 	if err != nil {
 		slog.Error("get time ranges", sl.Err(err))
@@ -36,6 +44,10 @@ func (h *Handler) ConfirmTime(c tele.Context) error {
 		slog.Error("edit time message", sl.Err(err))
 	}
 
+	if editing {
+		return h.sendProfile(c)
+	}
+
 	return c.Send(messages.M.Registration.Completed, view.RegistrationCompletedKeyboard(false))
 }
 
@@ -63,5 +75,6 @@ func (h *Handler) Time(c tele.Context) error {
 		return c.Respond()
 	}
 
-	return c.Edit(messages.M.Profile.Schedule.Request, view.TimeKeyboard(selected))
+	_, err = h.Bot.EditReplyMarkup(c.Message(), view.TimeKeyboard(selected))
+	return err
 }
@@ -74,3 +74,33 @@ func (h *Handler) AdminPanel(c tele.Context) error {
 
 	return c.Send(content, view.RefreshAdminKeyboard())
 }
+
+func (h *Handler) User(c tele.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return c.Send(messages.M.Admin.User.Usage)
+	}
+
+	username := strings.TrimPrefix(args[0], "@")
+
+	content, err := h.Admin.FormatUser(context.Background(), username)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return c.Send(messages.Format(messages.M.Error.UserNotFound, map[string]string{"username": username}))
+		}
+		slog.Error("format user", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(content)
+}
+
+func (h *Handler) Jobs(c tele.Context) error {
+	content, err := h.Admin.FormatJobs(context.Background())
+	if err != nil {
+		slog.Error("format jobs", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(content)
+}
@@ -0,0 +1,23 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+// Broadcast starts composing a broadcast: the admin's next message becomes its content.
+func (h *Handler) Broadcast(c tele.Context) error {
+	broadcast, err := h.Broadcasts.Compose(context.Background(), c.Sender().ID)
+	if err != nil {
+		slog.Error("compose broadcast", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(messages.M.Broadcast.AskContent, view.CancelBroadcastKeyboard(fmt.Sprintf("%d", broadcast.ID)))
+}
@@ -1,8 +1,10 @@
 package command
 
 import (
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/invite"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/placephoto"
 	"github.com/jus1d/kypidbot/internal/domain"
-	"github.com/jus1d/kypidbot/internal/infrastructure/s3"
 	"github.com/jus1d/kypidbot/internal/usecase"
 	tele "gopkg.in/telebot.v3"
 )
@@ -12,8 +14,15 @@ type Handler struct {
 	Admin        *usecase.Admin
 	Matching     *usecase.Matching
 	Meeting      *usecase.Meeting
+	Places       *usecase.Places
+	Relay        *usecase.Relay
+	Rating       *usecase.Rating
+	Moderation   *usecase.Moderation
+	Broadcasts   *usecase.Broadcasts
+	Preferences  *usecase.Preferences
 	Settings     domain.SettingsRepository
-	Places       domain.PlaceRepository
 	Bot          *tele.Bot
-	S3           *s3.Client
+	Photos       *placephoto.Sender
+	Outbox       *outbox.Sender
+	Invites      *invite.Sender
 }
@@ -38,6 +38,9 @@ func (h *Handler) MatchPairs(c tele.Context) error {
 	if result.FullMatchCount > 0 {
 		fullInfo = fmt.Sprintf("\n\nполных совпадений (без общего времени): %d", result.FullMatchCount)
 	}
+	if result.ExcludedCount > 0 {
+		fullInfo += fmt.Sprintf("\n\nисключено из-за ненадёжности: %d", result.ExcludedCount)
+	}
 
 	if err := c.Send(messages.Format(messages.M.Matching.Success.Matched, map[string]string{
 		"pairs":     fmt.Sprintf("%d", result.PairsCount),
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) Reports(c tele.Context) error {
+	ctx := context.Background()
+
+	report, pending, err := h.Moderation.NextPending(ctx)
+	if err != nil {
+		slog.Error("get next report", sl.Err(err))
+		return nil
+	}
+	if report == nil {
+		return c.Send(messages.M.Moderation.Queue.Empty)
+	}
+
+	content, err := h.Moderation.FormatReport(ctx, report, pending)
+	if err != nil {
+		slog.Error("format report", sl.Err(err), "report_id", report.ID)
+		return nil
+	}
+	return c.Send(content, view.ReportReviewKeyboard(fmt.Sprintf("%d", report.ID)))
+}
+
+func (h *Handler) Unban(c tele.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return c.Send(messages.M.Moderation.Unban.Usage)
+	}
+
+	username := strings.TrimPrefix(args[0], "@")
+
+	err := h.Moderation.Unban(context.Background(), username)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return c.Send(messages.Format(messages.M.Error.UserNotFound, map[string]string{"username": username}))
+		case errors.Is(err, usecase.ErrNotBanned):
+			return c.Send(messages.Format(messages.M.Moderation.Unban.NotBanned, map[string]string{"username": username}))
+		default:
+			slog.Error("unban", sl.Err(err))
+			return nil
+		}
+	}
+
+	return c.Send(messages.Format(messages.M.Moderation.Unban.Success, map[string]string{"username": username}))
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) PlaceCatalog(c tele.Context) error {
+	ctx := context.Background()
+
+	place, page, total, err := h.Places.CatalogPage(ctx, 0)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPlaceNotFound) {
+			return c.Send(messages.M.Admin.Places.Empty)
+		}
+		slog.Error("get place catalog", sl.Err(err))
+		return c.Send("Ошибка при получении мест")
+	}
+
+	_, err = h.Photos.Send(ctx, c.Recipient(), place, h.Places.FormatCard(place), view.PlaceCatalogKeyboard(place, page, total))
+	return err
+}
+
+func (h *Handler) AddPlace(c tele.Context) error {
+	ctx := context.Background()
+
+	if c.Message().Payload == "" {
+		return c.Send(messages.M.Admin.Places.AddUsage)
+	}
+
+	created, err := h.Places.Create(ctx, c.Message().Payload)
+	if err != nil {
+		if errors.Is(err, usecase.ErrEmptyValue) {
+			return c.Send(messages.M.Admin.Places.AddUsage)
+		}
+		slog.Error("create place", sl.Err(err))
+		return c.Send("Ошибка при добавлении места")
+	}
+
+	place, page, total, err := h.Places.CatalogPageOf(ctx, created.ID)
+	if err != nil {
+		slog.Error("get place catalog", sl.Err(err))
+		return nil
+	}
+
+	_, err = h.Photos.Send(ctx, c.Recipient(), place, h.Places.FormatCard(place), view.PlaceCatalogKeyboard(place, page, total))
+	return err
+}
@@ -0,0 +1,21 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) Notifications(c tele.Context) error {
+	muted, err := h.Preferences.Muted(context.Background(), c.Sender().ID)
+	if err != nil {
+		slog.Error("get muted categories", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(messages.M.Preferences.Title, view.PreferencesKeyboard(muted))
+}
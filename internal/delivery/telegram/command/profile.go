@@ -0,0 +1,23 @@
+package command
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) Profile(c tele.Context) error {
+	user, err := h.Registration.GetUser(context.Background(), c.Sender().ID)
+	if err != nil {
+		slog.Error("get user", sl.Err(err))
+		return nil
+	}
+	if user == nil {
+		return nil
+	}
+
+	return c.Send(h.Registration.FormatProfile(user), view.ProfileKeyboard(user))
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) RelayLog(c tele.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return c.Send(messages.M.Relay.Log.Usage)
+	}
+
+	ctx := context.Background()
+
+	var (
+		content string
+		err     error
+	)
+	if meetingID, perr := strconv.ParseInt(args[0], 10, 64); perr == nil {
+		content, err = h.Relay.FormatMeetingLog(ctx, meetingID)
+	} else {
+		username := strings.TrimPrefix(args[0], "@")
+		content, err = h.Relay.FormatUserLog(ctx, username)
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			return c.Send(messages.Format(messages.M.Error.UserNotFound, map[string]string{"username": username}))
+		}
+	}
+	if err != nil {
+		slog.Error("format relay log", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(content)
+}
@@ -11,7 +11,9 @@ import (
 )
 
 func (h *Handler) Remind(c tele.Context) error {
-	users, err := h.Registration.GetUnregisteredUsers(context.Background())
+	ctx := context.Background()
+
+	users, err := h.Registration.GetUnregisteredUsers(ctx)
 	if err != nil {
 		slog.Error("get unregistered users", sl.Err(err))
 		return c.Send("Ошибка при получении пользователей")
@@ -27,9 +29,8 @@ func (h *Handler) Remind(c tele.Context) error {
 			continue
 		}
 
-		_, err := h.Bot.Send(&tele.User{ID: u.TelegramID}, messages.M.Notifications.Remind)
-		if err != nil {
-			slog.Error("send remind", sl.Err(err), "telegram_id", u.TelegramID)
+		if err := h.Outbox.Text(ctx, u.TelegramID, messages.M.Notifications.Remind, nil); err != nil {
+			slog.Error("queue remind", sl.Err(err), "telegram_id", u.TelegramID)
 			continue
 		}
 		count++
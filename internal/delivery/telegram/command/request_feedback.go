@@ -5,39 +5,51 @@ import (
 	"fmt"
 	"log/slog"
 
-	"github.com/jus1d/kypidbot/internal/config/messages"
-	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/invite"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
 	tele "gopkg.in/telebot.v3"
 )
 
+// RequestFeedback asks participants of already started meetings to rate them right
+// away, without waiting for the scheduled request. Participants already asked are skipped.
 func (h *Handler) RequestFeedback(c tele.Context) error {
 	ctx := context.Background()
 
-	telegramIDs, err := h.Meeting.GetTelegramIDsForFeedbackRequest(ctx)
+	meetings, err := h.Rating.MeetingsToRate(ctx, 0)
 	if err != nil {
-		slog.Error("get telegram ids for feedback request", sl.Err(err))
+		slog.Error("get meetings for feedback request", sl.Err(err))
 		return c.Send("Ошибка при получении списка пользователей")
 	}
 
-	if len(telegramIDs) == 0 {
-		return c.Send("Нет подходящих пар (оба подтвердили и хотя бы один отметился на месте).")
-	}
-
-	feedbackMsg := messages.M.Feedback.Request
-
 	sent := 0
-	for _, id := range telegramIDs {
-		if _, err := h.Bot.Send(&tele.User{ID: id}, feedbackMsg); err != nil {
-			slog.Error("send feedback request", sl.Err(err), "telegram_id", id)
-			continue
+	for _, m := range meetings {
+		for _, id := range m.FeedbackRecipients() {
+			partner, err := h.Meeting.GetPartner(ctx, m.ID, id)
+			if err != nil || partner == nil {
+				slog.Error("get partner for feedback request", sl.Err(err), "meeting_id", m.ID, "telegram_id", id)
+				continue
+			}
+
+			msg, err := invite.RatingRequest(id, m.ID, partner)
+			if err != nil {
+				slog.Error("make feedback request", sl.Err(err), "meeting_id", m.ID, "telegram_id", id)
+				continue
+			}
+
+			queued, err := h.Rating.Request(ctx, m.ID, id, msg)
+			if err != nil {
+				slog.Error("queue feedback request", sl.Err(err), "meeting_id", m.ID, "telegram_id", id)
+				continue
+			}
+			if queued {
+				sent++
+			}
 		}
-		if err := h.Registration.SetState(ctx, id, domain.UserStateAwaitingFeedback); err != nil {
-			slog.Error("set state awaiting_feedback", sl.Err(err), "telegram_id", id)
-			continue
-		}
-		sent++
 	}
 
-	return c.Send(fmt.Sprintf("Запрос отзыва отправлен %d пользователям.", sent))
+	if sent == 0 {
+		return c.Send("Нет участников, которых ещё не просили оценить встречу.")
+	}
+
+	return c.Send(fmt.Sprintf("Запрос отзыва поставлен в очередь на отправку %d пользователям.", sent))
 }
@@ -1,16 +1,12 @@
 package command
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 
 	"github.com/jus1d/kypidbot/internal/config/messages"
-	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
-	"github.com/jus1d/kypidbot/internal/domain"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
 	"github.com/jus1d/kypidbot/internal/usecase"
 	tele "gopkg.in/telebot.v3"
@@ -30,87 +26,17 @@ func (h *Handler) SendInvites(c tele.Context) error {
 	count := 0
 
 	for _, m := range meetResult.Meetings {
-		content := fmt.Sprintf("%s\n%s", messages.M.Meeting.Invite.Message, messages.M.Meeting.Invite.WaitConfirmation)
-		message := messages.Format(content, map[string]string{
-			"place": m.Place,
-			"route": m.Route,
-			"time":  domain.Timef(m.Time),
-		})
+		h.Invites.Send(ctx, m)
 
-		kb := view.MeetingKeyboard(fmt.Sprintf("%d", m.MeetingID))
-
-		if m.PhotoURL != "" {
-			photoReader, err := h.S3.GetPhoto(ctx, m.PhotoURL)
-			if err != nil {
-				slog.Error("get photo from s3", sl.Err(err))
-				_, err := h.Bot.Send(&tele.User{ID: m.DillID}, message, kb)
-				if err != nil {
-					slog.Error("send meeting to dill", sl.Err(err), "telegram_id", m.DillID)
-				}
-				_, err = h.Bot.Send(&tele.User{ID: m.DoeID}, message, kb)
-				if err != nil {
-					slog.Error("send meeting to doe", sl.Err(err), "telegram_id", m.DoeID)
-				}
-			} else {
-				photoBytes, err := io.ReadAll(photoReader)
-				photoReader.Close()
-				if err != nil {
-					slog.Error("read photo bytes", sl.Err(err))
-					_, err := h.Bot.Send(&tele.User{ID: m.DillID}, message, kb)
-					if err != nil {
-						slog.Error("send meeting to dill", sl.Err(err), "telegram_id", m.DillID)
-					}
-					_, err = h.Bot.Send(&tele.User{ID: m.DoeID}, message, kb)
-					if err != nil {
-						slog.Error("send meeting to doe", sl.Err(err), "telegram_id", m.DoeID)
-					}
-				} else {
-					dillPhoto := &tele.Photo{File: tele.FromReader(bytes.NewReader(photoBytes)), Caption: message}
-					_, err := h.Bot.Send(&tele.User{ID: m.DillID}, dillPhoto, kb)
-					if err != nil {
-						slog.Error("send photo to dill", sl.Err(err), "telegram_id", m.DillID)
-					}
-
-					doePhoto := &tele.Photo{File: tele.FromReader(bytes.NewReader(photoBytes)), Caption: message}
-					_, err = h.Bot.Send(&tele.User{ID: m.DoeID}, doePhoto, kb)
-					if err != nil {
-						slog.Error("send photo to doe", sl.Err(err), "telegram_id", m.DoeID)
-					}
-				}
-			}
-		} else {
-			_, err := h.Bot.Send(&tele.User{ID: m.DillID}, message, kb)
-			if err != nil {
-				slog.Error("send meeting to dill", sl.Err(err), "telegram_id", m.DillID)
-			}
-			_, err = h.Bot.Send(&tele.User{ID: m.DoeID}, message, kb)
-			if err != nil {
-				slog.Error("send meeting to doe", sl.Err(err), "telegram_id", m.DoeID)
-			}
+		if err := h.Meeting.MarkInvited(ctx, m.MeetingID); err != nil {
+			slog.Error("mark invited", sl.Err(err), "meeting_id", m.MeetingID)
 		}
 
 		count++
 	}
 
 	for _, fm := range meetResult.FullMatches {
-		dillMsg := messages.Format(messages.M.Meeting.Special.FullMatchNoTime, map[string]string{
-			"partner_mention": messages.Mention(fm.DoeTelegramID, fm.DoeFirstName, fm.DoeUsername),
-		})
-
-		doeMsg := messages.Format(messages.M.Meeting.Special.FullMatchNoTime, map[string]string{
-			"partner_mention": messages.Mention(fm.DillTelegramID, fm.DillFirstName, fm.DillUsername),
-		})
-
-		_, err := h.Bot.Send(&tele.User{ID: fm.DillTelegramID}, dillMsg)
-		if err != nil {
-			slog.Error("send full match to dill", sl.Err(err), "telegram_id", fm.DillTelegramID)
-		}
-
-		_, err = h.Bot.Send(&tele.User{ID: fm.DoeTelegramID}, doeMsg)
-		if err != nil {
-			slog.Error("send full match to doe", sl.Err(err), "telegram_id", fm.DoeTelegramID)
-		}
-
+		h.Invites.SendFullMatch(ctx, fm)
 		count++
 	}
 
@@ -119,9 +45,8 @@ func (h *Handler) SendInvites(c tele.Context) error {
 		slog.Error("get unmatched users", sl.Err(err))
 	} else {
 		for _, id := range unmatchedIDs {
-			_, err := h.Bot.Send(&tele.User{ID: id}, messages.M.Matching.Success.NotMatched)
-			if err != nil {
-				slog.Error("send not matched", sl.Err(err), "telegram_id", id)
+			if err := h.Outbox.Text(ctx, id, messages.M.Matching.Success.NotMatched, nil); err != nil {
+				slog.Error("queue not matched", sl.Err(err), "telegram_id", id)
 			}
 		}
 	}
@@ -0,0 +1,148 @@
+package invite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/placephoto"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+// MessageKey is the user_messages key of the message currently representing
+// the meeting in the participant's chat.
+const MessageKey = "invite"
+
+// Sender delivers meeting messages. Bulk sends go through the outbox, while
+// edits and replies to the participant's own actions are sent right away.
+type Sender struct {
+	Photos       *placephoto.Sender
+	Outbox       *outbox.Sender
+	UserMessages domain.UserMessageRepository
+}
+
+// Send queues the meeting invite with the place photo and confirmation buttons to both participants.
+func (s *Sender) Send(ctx context.Context, m usecase.MeetingNotification) {
+	message, kb := content(m)
+
+	for _, id := range []int64{m.DillID, m.DoeID} {
+		s.send(ctx, id, m, message, kb)
+		s.SendVenue(ctx, id, m.Place)
+	}
+}
+
+// Update replaces both participants' invites with the meeting's new place and time.
+// The stored messages are edited in place, unless the place has changed and
+// the photo has to be sent again.
+func (s *Sender) Update(ctx context.Context, m usecase.MeetingNotification, placeChanged bool) {
+	message, kb := content(m)
+
+	for _, id := range []int64{m.DillID, m.DoeID} {
+		msgID, err := s.UserMessages.GetMessageID(ctx, m.MeetingID, id, MessageKey)
+		if err != nil {
+			slog.Error("get invite message id", sl.Err(err), "telegram_id", id)
+		}
+
+		if msgID != 0 {
+			stored := &tele.Message{ID: msgID, Chat: &tele.Chat{ID: id}}
+			if !placeChanged && s.edit(stored, message, kb) {
+				continue
+			}
+			_ = s.Photos.Bot.Delete(stored)
+		}
+
+		s.send(ctx, id, m, message, kb)
+		if placeChanged {
+			s.SendVenue(ctx, id, m.Place)
+		}
+	}
+}
+
+// SendVenue queues the place as a map pin, so the participant can open
+// directions in their map app. Places without coordinates are skipped.
+func (s *Sender) SendVenue(ctx context.Context, telegramID int64, place *domain.Place) {
+	if place == nil || !place.HasLocation() {
+		return
+	}
+
+	if err := s.Outbox.Venue(ctx, telegramID, place); err != nil {
+		slog.Error("queue place venue", sl.Err(err), "telegram_id", telegramID, "place_id", place.ID)
+	}
+}
+
+// SendCalendar sends the meeting's iCalendar file, so the participant can add it to their calendar.
+func (s *Sender) SendCalendar(telegramID int64, data []byte, caption string) {
+	doc := &tele.Document{
+		File:     tele.FromReader(bytes.NewReader(data)),
+		FileName: "meeting.ics",
+		MIME:     "text/calendar",
+		Caption:  caption,
+	}
+	if _, err := s.Photos.Bot.Send(&tele.User{ID: telegramID}, doc); err != nil {
+		slog.Error("send meeting calendar", sl.Err(err), "telegram_id", telegramID)
+	}
+}
+
+// RatingRequest returns the request to rate the meeting with the partner, to be
+// queued as a domain.DeliveryFeedbackRequest delivery.
+func RatingRequest(telegramID int64, meetingID int64, partner *domain.User) (domain.OutboxMessage, error) {
+	content := messages.Format(messages.M.Rating.Ask, map[string]string{
+		"partner_mention": messages.Mention(partner.TelegramID, partner.FirstName, partner.Username),
+	})
+	return outbox.TextMessage(telegramID, content, view.RatingStarsKeyboard(fmt.Sprintf("%d", meetingID)))
+}
+
+// SendFullMatch tells both participants of a full match who their partner is.
+func (s *Sender) SendFullMatch(ctx context.Context, fm usecase.FullMatchNotification) {
+	dillMsg := messages.Format(messages.M.Meeting.Special.FullMatchNoTime, map[string]string{
+		"partner_mention": messages.Mention(fm.DoeTelegramID, fm.DoeFirstName, fm.DoeUsername),
+	})
+
+	doeMsg := messages.Format(messages.M.Meeting.Special.FullMatchNoTime, map[string]string{
+		"partner_mention": messages.Mention(fm.DillTelegramID, fm.DillFirstName, fm.DillUsername),
+	})
+
+	if err := s.Outbox.Text(ctx, fm.DillTelegramID, dillMsg, nil); err != nil {
+		slog.Error("queue full match to dill", sl.Err(err), "telegram_id", fm.DillTelegramID)
+	}
+
+	if err := s.Outbox.Text(ctx, fm.DoeTelegramID, doeMsg, nil); err != nil {
+		slog.Error("queue full match to doe", sl.Err(err), "telegram_id", fm.DoeTelegramID)
+	}
+}
+
+// send queues the invite, its message ID is stored under MessageKey once it is delivered.
+func (s *Sender) send(ctx context.Context, telegramID int64, m usecase.MeetingNotification, message string, kb *tele.ReplyMarkup) {
+	if err := s.Outbox.Place(ctx, telegramID, m.Place, message, kb, m.MeetingID, MessageKey); err != nil {
+		slog.Error("queue meeting invite", sl.Err(err), "telegram_id", telegramID)
+	}
+}
+
+// edit updates the caption of a photo invite or the text of a plain one.
+func (s *Sender) edit(stored *tele.Message, message string, kb *tele.ReplyMarkup) bool {
+	if _, err := s.Photos.Bot.EditCaption(stored, message, kb); err == nil {
+		return true
+	}
+	if _, err := s.Photos.Bot.Edit(stored, message, kb); err == nil {
+		return true
+	}
+	return false
+}
+
+func content(m usecase.MeetingNotification) (string, *tele.ReplyMarkup) {
+	text := fmt.Sprintf("%s\n%s", messages.M.Meeting.Invite.Message, messages.M.Meeting.Invite.WaitConfirmation)
+	message := messages.Format(text, map[string]string{
+		"place": m.Place.Description,
+		"route": m.Place.Route,
+		"time":  domain.Timef(m.Time),
+	})
+
+	return message, view.MeetingKeyboard(fmt.Sprintf("%d", m.MeetingID))
+}
@@ -0,0 +1,50 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+// handleBroadcastContent takes the admin's message, as is, as the content of their broadcast.
+func (h *Handler) handleBroadcastContent(c tele.Context, sender *tele.User) error {
+	broadcast, err := h.Broadcasts.SetContent(context.Background(), sender.ID, c.Chat().ID, c.Message().ID)
+	if err != nil {
+		slog.Error("set broadcast content", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(messages.M.Broadcast.AskSegment, view.BroadcastSegmentsKeyboard(fmt.Sprintf("%d", broadcast.ID)))
+}
+
+func (h *Handler) handleBroadcastTime(c tele.Context, sender *tele.User) error {
+	ctx := context.Background()
+
+	broadcast, err := h.Broadcasts.Schedule(ctx, sender.ID, c.Text())
+	if err != nil {
+		if !errors.Is(err, usecase.ErrInvalidScheduleTime) {
+			slog.Error("schedule broadcast", sl.Err(err))
+			return nil
+		}
+
+		draft, err := h.Broadcasts.Draft(ctx, sender.ID)
+		if err != nil || draft == nil {
+			slog.Error("get broadcast draft", sl.Err(err))
+			return nil
+		}
+		return c.Send(messages.M.Broadcast.InvalidTime, view.CancelBroadcastKeyboard(fmt.Sprintf("%d", draft.ID)))
+	}
+
+	return c.Send(messages.Format(messages.M.Broadcast.Scheduled, map[string]string{
+		"id":   fmt.Sprintf("%d", broadcast.ID),
+		"time": domain.Timef(*broadcast.ScheduledAt),
+	}), view.CancelBroadcastKeyboard(fmt.Sprintf("%d", broadcast.ID)))
+}
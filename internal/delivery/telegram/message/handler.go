@@ -1,6 +1,7 @@
 package message
 
 import (
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/placephoto"
 	"github.com/jus1d/kypidbot/internal/domain"
 	"github.com/jus1d/kypidbot/internal/usecase"
 	tele "gopkg.in/telebot.v3"
@@ -9,7 +10,11 @@ import (
 type Handler struct {
 	Registration *usecase.Registration
 	Meeting      *usecase.Meeting
+	Places       *usecase.Places
+	Relay        *usecase.Relay
+	Rating       *usecase.Rating
+	Broadcasts   *usecase.Broadcasts
 	Users        domain.UserRepository
-	Feedback     domain.FeedbackRepository
 	Bot          *tele.Bot
+	Photos       *placephoto.Sender
 }
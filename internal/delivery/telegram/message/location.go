@@ -0,0 +1,102 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) Location(c tele.Context) error {
+	state, err := h.Registration.GetState(context.Background(), c.Sender().ID)
+	if err != nil {
+		slog.Error("get state", sl.Err(err))
+		return nil
+	}
+	if state == domain.UserStateAwaitingPlaceEdit {
+		return h.handlePlaceLocation(c, c.Sender())
+	}
+
+	return h.checkIn(c, true)
+}
+
+// EditedLocation handles live location updates, so the user is checked in
+// as soon as they come close enough to the place.
+func (h *Handler) EditedLocation(c tele.Context) error {
+	if c.Message().Location == nil {
+		return nil
+	}
+	return h.checkIn(c, false)
+}
+
+// checkIn verifies the shared location. The partner is told how far away the user is
+// on every explicitly shared location, but only on arrival for live updates.
+func (h *Handler) checkIn(c tele.Context, notifyPartner bool) error {
+	sender := c.Sender()
+	ctx := context.Background()
+
+	state, err := h.Registration.GetState(ctx, sender.ID)
+	if err != nil {
+		slog.Error("get state", sl.Err(err))
+		return nil
+	}
+	if state != domain.UserStateAwaitingLocation {
+		return nil
+	}
+
+	location := c.Message().Location
+	result, err := h.Meeting.CheckIn(ctx, sender.ID, float64(location.Lat), float64(location.Lng))
+	if err != nil {
+		if domain.IsTransitionError(err) {
+			return c.Send(messages.M.Meeting.Status.Outdated, &tele.ReplyMarkup{RemoveKeyboard: true})
+		}
+		if !errors.Is(err, usecase.ErrNoPendingCheckIn) {
+			slog.Error("check in", sl.Err(err))
+		}
+		return nil
+	}
+
+	distance := messages.Distance(result.Distance)
+
+	if notifyPartner || result.Arrived {
+		_, err := h.Bot.Send(&tele.User{ID: result.PartnerID}, messages.Format(messages.M.Notifications.CheckIn.PartnerDistance, map[string]string{
+			"distance": distance,
+		}))
+		if err != nil {
+			slog.Error("send partner distance", sl.Err(err), "partner_id", result.PartnerID)
+		}
+	}
+
+	if !result.Arrived {
+		if !notifyPartner {
+			return nil
+		}
+		return c.Send(messages.Format(messages.M.Notifications.CheckIn.TooFar, map[string]string{
+			"distance": distance,
+		}), view.LocationKeyboard())
+	}
+
+	return c.Send(messages.M.Notifications.ArrivedAsk, &tele.ReplyMarkup{RemoveKeyboard: true})
+}
+
+func (h *Handler) handleSkipLocation(c tele.Context, sender *tele.User) error {
+	if c.Text() != messages.M.UI.Buttons.SkipLocation {
+		return c.Send(messages.M.Notifications.CheckIn.Ask, view.LocationKeyboard())
+	}
+
+	if err := h.Meeting.SkipCheckIn(context.Background(), sender.ID); err != nil {
+		if domain.IsTransitionError(err) {
+			return c.Send(messages.M.Meeting.Status.Outdated, &tele.ReplyMarkup{RemoveKeyboard: true})
+		}
+		slog.Error("skip check-in", sl.Err(err))
+		return nil
+	}
+
+	return c.Send(messages.M.Notifications.ArrivedAsk, &tele.ReplyMarkup{RemoveKeyboard: true})
+}
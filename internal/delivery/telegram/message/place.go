@@ -0,0 +1,113 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) Photo(c tele.Context) error {
+	ctx := context.Background()
+	sender := c.Sender()
+
+	state, err := h.Registration.GetState(ctx, sender.ID)
+	if err != nil {
+		slog.Error("get state", sl.Err(err))
+		return nil
+	}
+	switch state {
+	case domain.UserStateAwaitingPlaceEdit:
+	case domain.UserStateAwaitingBroadcast:
+		return h.handleBroadcastContent(c, sender)
+	default:
+		return nil
+	}
+
+	edit, err := h.Places.PendingEdit(ctx, sender.ID)
+	if err != nil {
+		slog.Error("get pending place edit", sl.Err(err))
+		return nil
+	}
+	if edit == nil || edit.Field != domain.PlaceFieldPhoto {
+		return nil
+	}
+
+	photo := c.Message().Photo
+	reader, err := h.Bot.File(&photo.File)
+	if err != nil {
+		slog.Error("download photo", sl.Err(err))
+		return nil
+	}
+	defer reader.Close()
+
+	place, err := h.Places.ApplyPhoto(ctx, sender.ID, reader, photo.FileSize, photo.FileID)
+	if err != nil {
+		slog.Error("apply place photo", sl.Err(err))
+		return nil
+	}
+
+	return h.sendPlaceCard(ctx, c, place.ID)
+}
+
+func (h *Handler) handlePlaceLocation(c tele.Context, sender *tele.User) error {
+	ctx := context.Background()
+
+	location := c.Message().Location
+	place, err := h.Places.ApplyLocation(ctx, sender.ID, float64(location.Lat), float64(location.Lng))
+	if err != nil {
+		if errors.Is(err, usecase.ErrNoPendingEdit) {
+			return nil
+		}
+		slog.Error("apply place location", sl.Err(err))
+		return nil
+	}
+
+	return h.sendPlaceCard(ctx, c, place.ID)
+}
+
+func (h *Handler) handlePlaceEdit(c tele.Context, sender *tele.User) error {
+	ctx := context.Background()
+
+	place, err := h.Places.ApplyText(ctx, sender.ID, c.Text())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidQuality):
+			return c.Send(messages.M.Admin.Places.InvalidQuality, view.CancelPlaceEditKeyboard())
+		case errors.Is(err, usecase.ErrInvalidLocation):
+			return c.Send(messages.M.Admin.Places.InvalidLocation, view.CancelPlaceEditKeyboard())
+		case errors.Is(err, usecase.ErrInvalidRadius):
+			return c.Send(messages.M.Admin.Places.InvalidRadius, view.CancelPlaceEditKeyboard())
+		case errors.Is(err, usecase.ErrNoPendingEdit):
+			return c.Send(messages.M.Admin.Places.ExpectedPhoto, view.CancelPlaceEditKeyboard())
+		case errors.Is(err, usecase.ErrEmptyValue):
+			return nil
+		default:
+			slog.Error("apply place edit", sl.Err(err))
+			return nil
+		}
+	}
+
+	return h.sendPlaceCard(ctx, c, place.ID)
+}
+
+func (h *Handler) sendPlaceCard(ctx context.Context, c tele.Context, placeID int64) error {
+	if err := c.Send(messages.M.Admin.Places.Saved); err != nil {
+		slog.Error("send place saved", sl.Err(err))
+	}
+
+	place, page, total, err := h.Places.CatalogPageOf(ctx, placeID)
+	if err != nil {
+		slog.Error("get place catalog", sl.Err(err))
+		return nil
+	}
+
+	_, err = h.Photos.Send(ctx, c.Recipient(), place, h.Places.FormatCard(place), view.PlaceCatalogKeyboard(place, page, total))
+	return err
+}
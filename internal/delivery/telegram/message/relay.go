@@ -0,0 +1,41 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (h *Handler) handleRelay(c tele.Context, sender *tele.User) error {
+	msg, err := h.Relay.Send(context.Background(), sender.ID, c.Text())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRelayClosed):
+			return c.Send(messages.M.Relay.Closed)
+		case errors.Is(err, usecase.ErrRateLimited):
+			return c.Send(messages.M.Relay.RateLimited, view.LeaveChatKeyboard())
+		default:
+			slog.Error("relay message", sl.Err(err))
+			return nil
+		}
+	}
+
+	content := messages.Format(messages.M.Relay.Incoming, map[string]string{
+		"text": html.EscapeString(msg.Text),
+	})
+
+	kb := view.ReplyKeyboard(fmt.Sprintf("%d", msg.MeetingID))
+	if _, err := h.Bot.Send(&tele.User{ID: msg.RecipientID}, content, kb); err != nil {
+		slog.Error("send relayed message", sl.Err(err), "recipient_id", msg.RecipientID)
+	}
+
+	return nil
+}
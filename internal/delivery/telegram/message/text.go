@@ -2,13 +2,16 @@ package message
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/jus1d/kypidbot/internal/config/messages"
 	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
 	"github.com/jus1d/kypidbot/internal/domain"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
 	tele "gopkg.in/telebot.v3"
 )
 
@@ -26,21 +29,88 @@ func (h *Handler) Text(c tele.Context) error {
 		return h.handleAppearance(c, sender)
 	case domain.UserStateAwaitingAbout:
 		return h.handleAbout(c, sender)
+	case domain.UserStateAwaitingAboutDetails:
+		return h.handleAboutDetails(c, sender)
 	case domain.UserStateAwaitingSupport:
 		return h.handleSupport(c, sender)
 	case domain.UserStateAwaitingFeedback:
 		return h.handleFeedback(c, sender)
+	case domain.UserStateAwaitingPlaceEdit:
+		return h.handlePlaceEdit(c, sender)
+	case domain.UserStateChatting:
+		return h.handleRelay(c, sender)
+	case domain.UserStateAwaitingLocation:
+		return h.handleSkipLocation(c, sender)
+	case domain.UserStateAwaitingBroadcast:
+		return h.handleBroadcastContent(c, sender)
+	case domain.UserStateAwaitingBroadcastTime:
+		return h.handleBroadcastTime(c, sender)
 	}
 
 	return nil
 }
 
 func (h *Handler) handleAbout(c tele.Context, sender *tele.User) error {
-	if err := h.Registration.SetAbout(context.Background(), sender.ID, c.Text()); err != nil {
+	followUp, err := h.Registration.CheckAbout(c.Text())
+	if err != nil {
+		return c.Send(h.Registration.FormatAboutError(err, c.Text()))
+	}
+
+	if followUp {
+		if err := h.Registration.AskAboutDetails(context.Background(), sender.ID, c.Text()); err != nil {
+			slog.Error("ask about details", sl.Err(err))
+			return nil
+		}
+		return c.Send(h.Registration.FollowUpQuestion(c.Text()))
+	}
+
+	return h.acceptAbout(c, sender, c.Text())
+}
+
+// handleAboutDetails adds the answer to the follow-up question to the about
+// text, which goes on however short it still is.
+func (h *Handler) handleAboutDetails(c tele.Context, sender *tele.User) error {
+	user, err := h.Registration.GetUser(context.Background(), sender.ID)
+	if err != nil || user == nil {
+		slog.Error("get user", sl.Err(err))
+		return nil
+	}
+
+	about := strings.TrimSpace(user.About + "\n\n" + c.Text())
+	if _, err := h.Registration.CheckAbout(about); err != nil && !errors.Is(err, usecase.ErrAboutTooShort) {
+		return c.Send(h.Registration.FormatAboutError(err, about))
+	}
+
+	return h.acceptAbout(c, sender, about)
+}
+
+// acceptAbout saves the about text and moves on to the schedule step, or back
+// to the profile if the user is editing it.
+func (h *Handler) acceptAbout(c tele.Context, sender *tele.User, about string) error {
+	if err := h.Registration.SetAbout(context.Background(), sender.ID, strings.TrimSpace(about)); err != nil {
 		slog.Error("set about", sl.Err(err))
 		return nil
 	}
 
+	editing, err := h.Registration.FinishEdit(context.Background(), sender.ID)
+	if err != nil {
+		slog.Error("finish edit", sl.Err(err))
+		return nil
+	}
+
+	if editing {
+		user, err := h.Registration.GetUser(context.Background(), sender.ID)
+		if err != nil || user == nil {
+			slog.Error("get user", sl.Err(err))
+			return nil
+		}
+
+		if err := c.Send(messages.M.Profile.About.Accepted); err != nil {
+			return err
+		}
+		return c.Send(h.Registration.FormatProfile(user), view.ProfileKeyboard(user))
+	}
+
 	if err := h.Registration.SetState(context.Background(), sender.ID, domain.UserStateAwaitingTime); err != nil {
 		slog.Error("set state", sl.Err(err))
 		return nil
@@ -115,7 +185,7 @@ func (h *Handler) handleSupport(c tele.Context, sender *tele.User) error {
 }
 
 func (h *Handler) handleFeedback(c tele.Context, sender *tele.User) error {
-	if err := h.Feedback.Save(context.Background(), sender.ID, c.Text()); err != nil {
+	if err := h.Rating.Comment(context.Background(), sender.ID, c.Text()); err != nil {
 		slog.Error("save feedback", sl.Err(err))
 		return nil
 	}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jus1d/kypidbot/internal/config/messages"
 	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
 	tele "gopkg.in/telebot.v3"
 )
 
@@ -27,7 +28,7 @@ func (b *Bot) RegistrationGuard(next tele.HandlerFunc) tele.HandlerFunc {
 		user, err := b.users.GetUser(ctx, c.Sender().ID)
 		if err == nil && user != nil {
 			switch user.State {
-			case domain.UserStateAwaitingAppearance, domain.UserStateAwaitingSupport:
+			case domain.UserStateAwaitingAppearance, domain.UserStateAwaitingSupport, domain.UserStateChatting, domain.UserStateAwaitingLocation:
 				return next(c)
 			case domain.UserStateCompleted:
 				return c.Send(messages.M.Registration.ClosedRegistered)
@@ -48,6 +49,54 @@ func (b *Bot) AdminOnly(next tele.HandlerFunc) tele.HandlerFunc {
 	}
 }
 
+// BanGuard stops banned users from using the bot.
+func (b *Bot) BanGuard(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		if c.Sender() == nil {
+			return next(c)
+		}
+
+		banned, err := b.moderation.IsBanned(context.Background(), c.Sender().ID)
+		if err != nil || !banned {
+			return next(c)
+		}
+
+		if c.Callback() != nil {
+			return c.Respond(&tele.CallbackResponse{Text: messages.M.Moderation.Banned})
+		}
+		return c.Send(messages.M.Moderation.Banned)
+	}
+}
+
+// Reachability clears the unreachable flag of a user who writes to the bot
+// again, e.g. after unblocking it.
+func (b *Bot) Reachability(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		if c.Sender() == nil {
+			return next(c)
+		}
+
+		ctx := context.Background()
+		unreachable, err := b.users.IsUnreachable(ctx, c.Sender().ID)
+		if err != nil {
+			slog.Error("check unreachable flag", sl.Err(err), "telegram_id", c.Sender().ID)
+			return next(c)
+		}
+		if !unreachable {
+			return next(c)
+		}
+
+		changed, err := b.users.SetUnreachable(ctx, c.Sender().ID, false)
+		if err != nil {
+			slog.Error("clear unreachable flag", sl.Err(err), "telegram_id", c.Sender().ID)
+		} else if changed {
+			slog.Info("user is reachable again", "telegram_id", c.Sender().ID)
+		}
+
+		return next(c)
+	}
+}
+
 func LogUpdates(next tele.HandlerFunc) tele.HandlerFunc {
 	return func(c tele.Context) error {
 		sender := c.Sender()
@@ -0,0 +1,79 @@
+// Package outbox delivers messages through a queue stored in Postgres, so
+// bulk sends survive restarts, respect the Telegram rate limits and are
+// retried when Telegram fails.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+	tele "gopkg.in/telebot.v3"
+)
+
+// Sender queues messages for the Worker.
+type Sender struct {
+	Repo domain.OutboxRepository
+}
+
+// Text queues a text message with an optional inline keyboard.
+func (s *Sender) Text(ctx context.Context, chatID int64, text string, kb *tele.ReplyMarkup) error {
+	m, err := TextMessage(chatID, text, kb)
+	if err != nil {
+		return err
+	}
+	return s.Repo.Enqueue(ctx, &m)
+}
+
+// Place queues the place photo with the caption. Once sent, the message ID
+// is stored as the participant's message of the meeting under key.
+func (s *Sender) Place(ctx context.Context, chatID int64, place *domain.Place, caption string, kb *tele.ReplyMarkup, meetingID int64, key string) error {
+	m, err := withMarkup(domain.OutboxMessage{
+		ChatID:     chatID,
+		Kind:       domain.OutboxKindPlace,
+		Text:       caption,
+		PlaceID:    &place.ID,
+		MeetingID:  &meetingID,
+		MessageKey: key,
+	}, kb)
+	if err != nil {
+		return err
+	}
+	return s.Repo.Enqueue(ctx, &m)
+}
+
+// Venue queues the place as a map pin.
+func (s *Sender) Venue(ctx context.Context, chatID int64, place *domain.Place) error {
+	m := VenueMessage(chatID, place)
+	return s.Repo.Enqueue(ctx, &m)
+}
+
+// TextMessage returns a text message with an optional inline keyboard, for
+// queueing along with a delivery, see domain.DeliveryRepository.
+func TextMessage(chatID int64, text string, kb *tele.ReplyMarkup) (domain.OutboxMessage, error) {
+	return withMarkup(domain.OutboxMessage{
+		ChatID: chatID,
+		Kind:   domain.OutboxKindText,
+		Text:   text,
+	}, kb)
+}
+
+// VenueMessage returns the place as a map pin, like TextMessage.
+func VenueMessage(chatID int64, place *domain.Place) domain.OutboxMessage {
+	return domain.OutboxMessage{
+		ChatID:  chatID,
+		Kind:    domain.OutboxKindVenue,
+		PlaceID: &place.ID,
+	}
+}
+
+func withMarkup(m domain.OutboxMessage, kb *tele.ReplyMarkup) (domain.OutboxMessage, error) {
+	if kb != nil {
+		markup, err := json.Marshal(kb)
+		if err != nil {
+			return domain.OutboxMessage{}, err
+		}
+		m.Markup = markup
+	}
+	return m, nil
+}
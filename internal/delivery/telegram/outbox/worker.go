@@ -0,0 +1,234 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/config"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/placephoto"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/tgerr"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/lib/ratelimit"
+	tele "gopkg.in/telebot.v3"
+)
+
+// lease is how long a claimed message is hidden from other claims. A message
+// left unsent, e.g. because of a shutdown, is delivered again after it.
+const lease = time.Minute
+
+type Worker struct {
+	config       *config.Outbox
+	photos       *placephoto.Sender
+	users        domain.UserRepository
+	userMessages domain.UserMessageRepository
+	repo         domain.OutboxRepository
+	limiter      *ratelimit.Limiter
+}
+
+func NewWorker(c *config.Outbox, photos *placephoto.Sender, users domain.UserRepository, userMessages domain.UserMessageRepository, repo domain.OutboxRepository) *Worker {
+	return &Worker{
+		config:       c,
+		photos:       photos,
+		users:        users,
+		userMessages: userMessages,
+		repo:         repo,
+		limiter:      ratelimit.New(c.GlobalRate, c.ChatInterval),
+	}
+}
+
+// Run delivers due messages until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		list, err := w.repo.ClaimDue(ctx, w.config.BatchSize, lease)
+		if err != nil {
+			slog.Error("outbox: claim due messages", sl.Err(err))
+		}
+
+		paused := false
+		for i, m := range list {
+			if ctx.Err() != nil {
+				return
+			}
+
+			// waiting out a flood pause could outlast the lease of the rest of the
+			// batch, so they are given back instead of being sent by two workers
+			if pause := w.deliver(ctx, &m); pause > 0 {
+				w.release(ctx, list[i+1:], time.Now().Add(pause))
+				sleep(ctx, pause)
+				paused = true
+				break
+			}
+		}
+
+		if !paused && len(list) < w.config.BatchSize {
+			sleep(ctx, w.config.PollInterval)
+		}
+	}
+}
+
+// release puts claimed messages back to the queue until at.
+func (w *Worker) release(ctx context.Context, list []domain.OutboxMessage, at time.Time) {
+	for _, m := range list {
+		if err := w.repo.Retry(ctx, m.ID, at, false, ""); err != nil {
+			slog.Error("outbox: release message", sl.Err(err), slog.Int64("outbox_id", m.ID))
+		}
+	}
+}
+
+// deliver sends the message and records the outcome. It returns how long
+// sending is paused if Telegram reported a flood limit, and 0 otherwise.
+func (w *Worker) deliver(ctx context.Context, m *domain.OutboxMessage) time.Duration {
+	log := slog.With(slog.Int64("outbox_id", m.ID), slog.Int64("chat_id", m.ChatID))
+
+	// a chat that got a message too recently waits without using up an attempt
+	if delay := w.limiter.Reserve(m.ChatID); delay > 0 {
+		if err := w.repo.Retry(ctx, m.ID, time.Now().Add(delay), false, ""); err != nil {
+			log.Error("outbox: postpone message", sl.Err(err))
+		}
+		return 0
+	}
+
+	if err := w.limiter.Wait(ctx); err != nil {
+		return 0
+	}
+
+	msg, err := w.send(ctx, m)
+	if err == nil {
+		if err := w.repo.MarkSent(ctx, m.ID, msg.ID); err != nil {
+			log.Error("outbox: mark sent", sl.Err(err))
+		}
+
+		if m.MeetingID != nil && m.MessageKey != "" {
+			if err := w.userMessages.StoreMessageID(ctx, *m.MeetingID, m.ChatID, m.MessageKey, msg.ID); err != nil {
+				log.Error("outbox: store message id", sl.Err(err), slog.String("key", m.MessageKey))
+			}
+		}
+		return 0
+	}
+
+	var flood tele.FloodError
+	if errors.As(err, &flood) {
+		delay := time.Duration(flood.RetryAfter) * time.Second
+		w.limiter.Pause(delay)
+
+		log.Warn("outbox: flood limit exceeded", slog.Duration("retry_after", delay))
+		if err := w.repo.Retry(ctx, m.ID, time.Now().Add(delay), false, err.Error()); err != nil {
+			log.Error("outbox: postpone message", sl.Err(err))
+		}
+		return delay
+	}
+
+	if tgerr.IsUnreachable(err) {
+		log.Info("outbox: user is unreachable", sl.Err(err))
+		if err := w.repo.MarkFailed(ctx, m.ID, err.Error()); err != nil {
+			log.Error("outbox: mark failed", sl.Err(err))
+		}
+
+		if _, err := w.users.SetUnreachable(ctx, m.ChatID, true); err != nil {
+			log.Error("outbox: mark user unreachable", sl.Err(err))
+		}
+		return 0
+	}
+
+	if tgerr.IsPermanent(err) || m.Attempts+1 >= w.config.MaxAttempts {
+		log.Error("outbox: message failed", sl.Err(err), slog.Int("attempts", m.Attempts+1))
+		if err := w.repo.MarkFailed(ctx, m.ID, err.Error()); err != nil {
+			log.Error("outbox: mark failed", sl.Err(err))
+		}
+		return 0
+	}
+
+	backoff := w.config.BaseBackoff << m.Attempts
+	log.Warn("outbox: send failed, retrying", sl.Err(err), slog.Duration("backoff", backoff))
+	if err := w.repo.Retry(ctx, m.ID, time.Now().Add(backoff), true, err.Error()); err != nil {
+		log.Error("outbox: schedule retry", sl.Err(err))
+	}
+	return 0
+}
+
+func (w *Worker) send(ctx context.Context, m *domain.OutboxMessage) (*tele.Message, error) {
+	to := &tele.User{ID: m.ChatID}
+
+	var opts []any
+	if len(m.Markup) > 0 {
+		kb := &tele.ReplyMarkup{}
+		if err := json.Unmarshal(m.Markup, kb); err != nil {
+			return nil, err
+		}
+		opts = append(opts, kb)
+	}
+
+	switch m.Kind {
+	case domain.OutboxKindText:
+		return w.photos.Bot.Send(to, m.Text, opts...)
+	case domain.OutboxKindCopy:
+		if m.FromChatID == nil || m.FromMessageID == nil {
+			return nil, fmt.Errorf("outbox message %d has no source message", m.ID)
+		}
+
+		source := &tele.Message{ID: *m.FromMessageID, Chat: &tele.Chat{ID: *m.FromChatID}}
+		return w.photos.Bot.Copy(to, source, opts...)
+	case domain.OutboxKindPlace, domain.OutboxKindVenue:
+		place, err := w.place(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Kind == domain.OutboxKindPlace {
+			return w.photos.Send(ctx, to, place, m.Text, opts...)
+		}
+
+		return w.photos.Bot.Send(to, venue(place), opts...)
+	default:
+		return nil, fmt.Errorf("unknown outbox message kind: %s", m.Kind)
+	}
+}
+
+func (w *Worker) place(ctx context.Context, m *domain.OutboxMessage) (*domain.Place, error) {
+	if m.PlaceID == nil {
+		return nil, fmt.Errorf("outbox message %d has no place", m.ID)
+	}
+
+	place, err := w.photos.Places.GetPlace(ctx, *m.PlaceID)
+	if err != nil {
+		return nil, err
+	}
+	if place == nil {
+		return nil, fmt.Errorf("place %d not found", *m.PlaceID)
+	}
+	return place, nil
+}
+
+func venue(place *domain.Place) *tele.Venue {
+	address := place.Route
+	if address == "" {
+		address = place.Description
+	}
+
+	v := &tele.Venue{
+		Title:   place.Description,
+		Address: address,
+	}
+	if place.HasLocation() {
+		v.Location = tele.Location{Lat: float32(*place.Latitude), Lng: float32(*place.Longitude)}
+	}
+	return v
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
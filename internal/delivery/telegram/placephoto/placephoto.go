@@ -0,0 +1,78 @@
+package placephoto
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/tgerr"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+type Sender struct {
+	Bot    *tele.Bot
+	Store  domain.PhotoStore
+	Places domain.PlaceRepository
+}
+
+// Send sends the place photo with the given caption, falling back to a plain
+// text message if the place has no photo or it can't be loaded.
+//
+// The photo is uploaded from the store only once: the file_id Telegram returns is
+// stored on the place and reused for later sends.
+func (s *Sender) Send(ctx context.Context, to tele.Recipient, place *domain.Place, caption string, opts ...any) (*tele.Message, error) {
+	if place.PhotoURL == "" {
+		return s.Bot.Send(to, caption, opts...)
+	}
+
+	if place.PhotoFileID != "" {
+		photo := &tele.Photo{File: tele.File{FileID: place.PhotoFileID}, Caption: caption}
+		msg, err := s.Bot.Send(to, photo, opts...)
+		if err == nil || !isRejected(err) {
+			return msg, err
+		}
+
+		slog.Warn("cached photo rejected, falling back to store", sl.Err(err), slog.Int64("place_id", place.ID))
+	}
+
+	reader, err := s.Store.GetPhoto(ctx, place.PhotoURL)
+	if err != nil {
+		slog.Error("get photo from store", sl.Err(err), slog.Int64("place_id", place.ID))
+		return s.Bot.Send(to, caption, opts...)
+	}
+	defer reader.Close()
+
+	photo := &tele.Photo{File: tele.FromReader(reader), Caption: caption}
+	msg, err := s.Bot.Send(to, photo, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.Photo != nil && msg.Photo.FileID != place.PhotoFileID {
+		place.PhotoFileID = msg.Photo.FileID
+		if err := s.Places.SetPhotoFileID(ctx, place.ID, place.PhotoFileID); err != nil {
+			slog.Error("store photo file id", sl.Err(err), slog.Int64("place_id", place.ID))
+		}
+	}
+
+	return msg, nil
+}
+
+// isRejected reports whether Telegram refused the cached file_id itself (e.g.
+// an expired or foreign one), as opposed to any other bad request such as an
+// unknown chat or a broken caption, which a re-upload would not fix.
+func isRejected(err error) bool {
+	code, desc, ok := tgerr.Parse(err)
+	if !ok || code != http.StatusBadRequest {
+		return false
+	}
+
+	desc = strings.ToLower(desc)
+	return strings.Contains(desc, "wrong file identifier") ||
+		strings.Contains(desc, "wrong file_id") ||
+		strings.Contains(desc, "wrong remote file id") ||
+		strings.Contains(desc, "file reference expired")
+}
@@ -0,0 +1,38 @@
+package placephoto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func TestIsRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"known wrong file id", tele.ErrWrongFileID, true},
+		{"known wrong remote file id", tele.ErrWrongFileIDSymbol, true},
+		{"plain wrong remote file identifier", errors.New("telegram: Bad Request: wrong remote file identifier specified: can't unserialize it (400)"), true},
+		{"plain wrong file_id", errors.New("telegram: Bad Request: wrong file_id or the file is temporarily unavailable (400)"), true},
+		{"plain file reference expired", errors.New("telegram: Bad Request: FILE_REFERENCE_EXPIRED: file reference expired (400)"), true},
+		{"wrapped plain", fmt.Errorf("send photo: %w", errors.New("telegram: Bad Request: file reference expired (400)")), true},
+		{"other bad request", tele.ErrChatNotFound, false},
+		{"plain other bad request", errors.New("telegram: Bad Request: can't parse entities (400)"), false},
+		{"file id text with another code", errors.New("telegram: Internal: wrong file_id (500)"), false},
+		{"forbidden", tele.ErrBlockedByUser, false},
+		{"network", errors.New("telebot: dial tcp: i/o timeout"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRejected(tt.err); got != tt.want {
+				t.Errorf("isRejected(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
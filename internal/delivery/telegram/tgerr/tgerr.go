@@ -0,0 +1,55 @@
+// Package tgerr classifies errors returned by the Telegram Bot API.
+package tgerr
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// plain matches the error telebot makes of an API error whose description it
+// has no *tele.Error for, e.g. "telegram: Bad Request: file reference expired (400)".
+var plain = regexp.MustCompile(`telegram: (.*) \((\d{3})\)$`)
+
+// Parse returns the code and the description of an API error, whether telebot
+// returned it as a *tele.Error or as a plain error. Reports false for other
+// errors, e.g. network ones.
+func Parse(err error) (int, string, bool) {
+	if err == nil {
+		return 0, "", false
+	}
+
+	var terr *tele.Error
+	if errors.As(err, &terr) {
+		return terr.Code, terr.Description, true
+	}
+
+	m := plain.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, "", false
+	}
+	code, _ := strconv.Atoi(m[2])
+	return code, m[1], true
+}
+
+// IsUnreachable reports whether the user can't get messages from the bot:
+// they blocked it, deleted their account or never started a chat with it.
+func IsUnreachable(err error) bool {
+	code, desc, ok := Parse(err)
+	if !ok {
+		return false
+	}
+	return code == http.StatusForbidden ||
+		code == http.StatusBadRequest && strings.Contains(strings.ToLower(desc), "chat not found")
+}
+
+// IsPermanent reports whether retrying the request can't help: the user is
+// unreachable or Telegram rejected the request itself.
+func IsPermanent(err error) bool {
+	code, _, ok := Parse(err)
+	return ok && (code == http.StatusBadRequest || code == http.StatusForbidden)
+}
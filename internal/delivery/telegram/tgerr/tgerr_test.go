@@ -0,0 +1,86 @@
+package tgerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantDesc string
+		wantOK   bool
+	}{
+		{"known", tele.ErrBlockedByUser, 403, "Forbidden: bot was blocked by the user", true},
+		{"plain", errors.New("telegram: Bad Request: message is too long (400)"), 400, "Bad Request: message is too long", true},
+		{"wrapped plain", fmt.Errorf("send: %w", errors.New("telegram: Forbidden: user is deactivated forever (403)")), 403, "Forbidden: user is deactivated forever", true},
+		{"network", errors.New("telebot: Post \"https://api.telegram.org\": EOF"), 0, "", false},
+		{"nil", nil, 0, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, desc, ok := Parse(tt.err)
+			if code != tt.wantCode || desc != tt.wantDesc || ok != tt.wantOK {
+				t.Errorf("Parse(%v) = %d, %q, %v, want %d, %q, %v", tt.err, code, desc, ok, tt.wantCode, tt.wantDesc, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"known bad request", tele.ErrChatNotFound, true},
+		{"known forbidden", tele.ErrBlockedByUser, true},
+		{"plain bad request", errors.New("telegram: Bad Request: message is too long (400)"), true},
+		{"plain forbidden", errors.New("telegram: Forbidden: bot can't send messages to bots (403)"), true},
+		{"plain server error", errors.New("telegram: Internal Server Error (500)"), false},
+		{"known server error", tele.ErrInternal, false},
+		{"network", errors.New("telebot: dial tcp: i/o timeout"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanent(tt.err); got != tt.want {
+				t.Errorf("IsPermanent(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"known blocked", tele.ErrBlockedByUser, true},
+		{"known deactivated", tele.ErrUserIsDeactivated, true},
+		{"known chat not found", tele.ErrChatNotFound, true},
+		{"plain deactivated", errors.New("telegram: Forbidden: user is deactivated forever (403)"), true},
+		{"plain kicked", errors.New("telegram: Forbidden: bot was kicked from the group chat (403)"), true},
+		{"wrapped plain", fmt.Errorf("send: %w", errors.New("telegram: Forbidden: user is deactivated forever (403)")), true},
+		{"plain chat not found", errors.New("telegram: Bad Request: Chat Not Found (400)"), true},
+		{"other bad request", errors.New("telegram: Bad Request: message is too long (400)"), false},
+		{"server error", errors.New("telegram: Internal Server Error (500)"), false},
+		{"network", errors.New("telebot: dial tcp: i/o timeout"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnreachable(tt.err); got != tt.want {
+				t.Errorf("IsUnreachable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
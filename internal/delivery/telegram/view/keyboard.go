@@ -1,6 +1,9 @@
 package view
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/jus1d/kypidbot/internal/config/messages"
 	"github.com/jus1d/kypidbot/internal/domain"
 	tele "gopkg.in/telebot.v3"
@@ -63,8 +66,48 @@ func MeetingKeyboard(meetingID string) *tele.ReplyMarkup {
 
 	confirm := menu.Data(messages.M.UI.Buttons.ConfirmMeeting, "confirm_meeting", meetingID)
 	cancel := menu.Data(messages.M.UI.Buttons.CancelMeeting, "cancel_meeting", meetingID)
+	propose := menu.Data(messages.M.UI.Buttons.ProposeTime, "propose_time", meetingID)
 
-	menu.Inline(menu.Row(confirm, cancel))
+	menu.Inline(menu.Row(confirm, cancel), menu.Row(propose))
+	return menu
+}
+
+func SeeAgainKeyboard(meetingID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	yes := menu.Data(messages.M.UI.Buttons.SeeAgainYes, "see_again", meetingID, "yes")
+	no := menu.Data(messages.M.UI.Buttons.SeeAgainNo, "see_again", meetingID, "no")
+
+	menu.Inline(menu.Row(yes, no), moderationRow(menu, meetingID))
+	return menu
+}
+
+func ProposalSlotsKeyboard(meetingID string, slots []time.Time) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	var rows []tele.Row
+	for i := 0; i < len(slots); i += 3 {
+		var row tele.Row
+		for _, slot := range slots[i:min(i+3, len(slots))] {
+			row = append(row, menu.Data(slot.Format("15:04"), "propose_slot", meetingID, fmt.Sprintf("%d", slot.Unix())))
+		}
+		rows = append(rows, row)
+	}
+
+	keep := menu.Data(messages.M.UI.Buttons.KeepTime, "keep_time")
+	rows = append(rows, menu.Row(keep))
+
+	menu.Inline(rows...)
+	return menu
+}
+
+func ProposalKeyboard(proposalID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	accept := menu.Data(messages.M.UI.Buttons.AcceptTime, "accept_time", proposalID)
+	decline := menu.Data(messages.M.UI.Buttons.DeclineTime, "decline_time", proposalID)
+
+	menu.Inline(menu.Row(accept, decline))
 	return menu
 }
 
@@ -77,15 +120,40 @@ func CancelSupportKeyboard() *tele.ReplyMarkup {
 
 func CancelKeyboard(meetingID string) *tele.ReplyMarkup {
 	menu := &tele.ReplyMarkup{}
+	chat := menu.Data(messages.M.UI.Buttons.ChatPartner, "start_chat", meetingID)
 	cancel := menu.Data(messages.M.UI.Buttons.CancelMeeting, "cancel_meeting", meetingID)
-	menu.Inline(menu.Row(cancel))
+	menu.Inline(menu.Row(chat), menu.Row(cancel), moderationRow(menu, meetingID))
 	return menu
 }
 
 func ArrivedKeyboard(meetingID string) *tele.ReplyMarkup {
 	menu := &tele.ReplyMarkup{}
 	arrived := menu.Data(messages.M.UI.Buttons.Arrived, "arrived_meeting", meetingID)
-	menu.Inline(menu.Row(arrived))
+	chat := menu.Data(messages.M.UI.Buttons.ChatPartner, "start_chat", meetingID)
+	help := menu.Data(messages.M.Safety.Buttons.Help, "help", meetingID)
+	menu.Inline(menu.Row(arrived), menu.Row(chat), menu.Row(help), moderationRow(menu, meetingID))
+	return menu
+}
+
+func LocationKeyboard() *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+	share := menu.Location(messages.M.UI.Buttons.ShareLocation)
+	skip := menu.Text(messages.M.UI.Buttons.SkipLocation)
+	menu.Reply(menu.Row(share), menu.Row(skip))
+	return menu
+}
+
+func ReplyKeyboard(meetingID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	reply := menu.Data(messages.M.UI.Buttons.Reply, "start_chat", meetingID)
+	menu.Inline(menu.Row(reply), moderationRow(menu, meetingID))
+	return menu
+}
+
+func LeaveChatKeyboard() *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	leave := menu.Data(messages.M.UI.Buttons.LeaveChat, "leave_chat")
+	menu.Inline(menu.Row(leave))
 	return menu
 }
 
@@ -102,6 +170,29 @@ func RegistrationCompletedKeyboard(optedOut bool) *tele.ReplyMarkup {
 	return menu
 }
 
+// ProfileKeyboard edits the profile fields one by one. Participation can only
+// be changed once registration is complete.
+func ProfileKeyboard(u *domain.User) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	buttons := messages.M.Profile.View.Buttons
+
+	sex := menu.Data(buttons.Sex, "profile_edit", "sex")
+	about := menu.Data(buttons.About, "profile_edit", "about")
+	time := menu.Data(buttons.Time, "profile_edit", "time")
+	rows := []tele.Row{menu.Row(sex, about), menu.Row(time)}
+
+	if u.IsRegistered {
+		text := messages.M.UI.Buttons.OptOut
+		if u.OptedOut {
+			text = messages.M.UI.Buttons.OptIn
+		}
+		rows = append(rows, menu.Row(menu.Data(text, "profile_participation")))
+	}
+
+	menu.Inline(rows...)
+	return menu
+}
+
 func RefreshAdminKeyboard() *tele.ReplyMarkup {
 	menu := &tele.ReplyMarkup{}
 	btn := menu.Data("Обновить", "refresh_admin")
@@ -112,6 +203,180 @@ func RefreshAdminKeyboard() *tele.ReplyMarkup {
 func CantFindKeyboard(meetingID string) *tele.ReplyMarkup {
 	menu := &tele.ReplyMarkup{}
 	btn := menu.Data(messages.M.UI.Buttons.CantFind, "cant_find_partner", meetingID)
+	help := menu.Data(messages.M.Safety.Buttons.Help, "help", meetingID)
+	menu.Inline(menu.Row(btn), menu.Row(help))
+	return menu
+}
+
+func PlaceCatalogKeyboard(place *domain.Place, page int, total int) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	buttons := messages.M.Admin.Places.Buttons
+	id := fmt.Sprintf("%d", place.ID)
+
+	prev := menu.Data("◀", "places_page", fmt.Sprintf("%d", (page-1+total)%total))
+	counter := menu.Data(fmt.Sprintf("%d / %d", page+1, total), "places_page", fmt.Sprintf("%d", page))
+	next := menu.Data("▶", "places_page", fmt.Sprintf("%d", (page+1)%total))
+
+	description := menu.Data(buttons.Description, "place_edit", id, string(domain.PlaceFieldDescription))
+	route := menu.Data(buttons.Route, "place_edit", id, string(domain.PlaceFieldRoute))
+	quality := menu.Data(buttons.Quality, "place_edit", id, string(domain.PlaceFieldQuality))
+	photo := menu.Data(buttons.Photo, "place_edit", id, string(domain.PlaceFieldPhoto))
+	location := menu.Data(buttons.Location, "place_edit", id, string(domain.PlaceFieldLocation))
+	radius := menu.Data(buttons.Radius, "place_edit", id, string(domain.PlaceFieldRadius))
+
+	toggleText := buttons.Disable
+	if !place.IsActive {
+		toggleText = buttons.Enable
+	}
+	toggle := menu.Data(toggleText, "place_toggle", id)
+
+	menu.Inline(
+		menu.Row(prev, counter, next),
+		menu.Row(description, route),
+		menu.Row(quality, photo),
+		menu.Row(location, radius),
+		menu.Row(toggle),
+	)
+	return menu
+}
+
+func CancelPlaceEditKeyboard() *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	btn := menu.Data(messages.M.Admin.Places.Buttons.Cancel, "cancel_place_edit")
+	menu.Inline(menu.Row(btn))
+	return menu
+}
+
+func RatingStarsKeyboard(meetingID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	var row tele.Row
+	for stars := 1; stars <= 5; stars++ {
+		row = append(row, menu.Data(fmt.Sprintf("%d⭐", stars), "rate", meetingID, fmt.Sprintf("%d", stars)))
+	}
+
+	menu.Inline(row)
+	return menu
+}
+
+func RatingTagsKeyboard(meetingID string, rating *domain.Rating) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	var rows []tele.Row
+	for _, tag := range domain.RatingTags {
+		text := messages.M.Rating.TagLabels[string(tag)]
+		if rating.HasTag(tag) {
+			text = "> " + text + " <"
+		}
+		rows = append(rows, menu.Row(menu.Data(text, "rate_tag", meetingID, string(tag))))
+	}
+
+	done := menu.Data(messages.M.Rating.Buttons.Done, "rate_done", meetingID)
+	rows = append(rows, menu.Row(done))
+
+	menu.Inline(rows...)
+	return menu
+}
+
+func SkipCommentKeyboard() *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	btn := menu.Data(messages.M.Rating.Buttons.Skip, "rate_skip")
+	menu.Inline(menu.Row(btn))
+	return menu
+}
+
+// moderationRow lets the participant report or block their partner in the meeting.
+func moderationRow(menu *tele.ReplyMarkup, meetingID string) tele.Row {
+	report := menu.Data(messages.M.Moderation.Buttons.Report, "report", meetingID)
+	block := menu.Data(messages.M.Moderation.Buttons.Block, "block", meetingID)
+	return menu.Row(report, block)
+}
+
+func ReportReasonsKeyboard(meetingID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	var rows []tele.Row
+	for _, reason := range domain.ReportReasons {
+		btn := menu.Data(messages.M.Moderation.Reasons[string(reason)], "report_reason", meetingID, string(reason))
+		rows = append(rows, menu.Row(btn))
+	}
+
+	cancel := menu.Data(messages.M.Moderation.Buttons.Cancel, "report_cancel")
+	rows = append(rows, menu.Row(cancel))
+
+	menu.Inline(rows...)
+	return menu
+}
+
+func ReportReviewKeyboard(reportID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	buttons := messages.M.Moderation.Buttons
+
+	dismiss := menu.Data(buttons.Dismiss, "report_resolve", reportID, string(domain.ReportStatusDismissed))
+	warn := menu.Data(buttons.Warn, "report_resolve", reportID, string(domain.ReportStatusWarned))
+	ban := menu.Data(buttons.Ban, "report_resolve", reportID, string(domain.ReportStatusBanned))
+
+	menu.Inline(menu.Row(dismiss), menu.Row(warn, ban))
+	return menu
+}
+
+func AcknowledgeIncidentKeyboard(incidentID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	btn := menu.Data(messages.M.Safety.Buttons.Acknowledge, "incident_ack", incidentID)
 	menu.Inline(menu.Row(btn))
 	return menu
 }
+
+func BroadcastSegmentsKeyboard(broadcastID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	var rows []tele.Row
+	for _, segment := range domain.BroadcastSegments {
+		btn := menu.Data(messages.M.Broadcast.Segments[string(segment)], "broadcast_segment", broadcastID, string(segment))
+		rows = append(rows, menu.Row(btn))
+	}
+
+	cancel := menu.Data(messages.M.Broadcast.Buttons.Cancel, "broadcast_cancel", broadcastID)
+	rows = append(rows, menu.Row(cancel))
+
+	menu.Inline(rows...)
+	return menu
+}
+
+func BroadcastConfirmKeyboard(broadcastID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	buttons := messages.M.Broadcast.Buttons
+
+	send := menu.Data(buttons.Send, "broadcast_send", broadcastID)
+	schedule := menu.Data(buttons.Schedule, "broadcast_schedule", broadcastID)
+	cancel := menu.Data(buttons.Cancel, "broadcast_cancel", broadcastID)
+
+	menu.Inline(menu.Row(send), menu.Row(schedule), menu.Row(cancel))
+	return menu
+}
+
+func CancelBroadcastKeyboard(broadcastID string) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+	btn := menu.Data(messages.M.Broadcast.Buttons.Cancel, "broadcast_cancel", broadcastID)
+	menu.Inline(menu.Row(btn))
+	return menu
+}
+
+// PreferencesKeyboard toggles the notification categories, muted ones are marked off.
+func PreferencesKeyboard(muted map[domain.NotificationCategory]bool) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{}
+
+	var rows []tele.Row
+	for _, category := range domain.NotificationCategories {
+		text := messages.M.Preferences.On
+		if muted[category] {
+			text = messages.M.Preferences.Off
+		}
+		text = messages.Format(text, map[string]string{"category": messages.M.Preferences.Categories[string(category)]})
+
+		rows = append(rows, menu.Row(menu.Data(text, "notification_toggle", string(category))))
+	}
+
+	menu.Inline(rows...)
+	return menu
+}
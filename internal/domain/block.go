@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// Block is a participant's wish never to deal with another user again:
+// the two are not matched and cannot message each other through the bot.
+type Block struct {
+	BlockerID int64
+	BlockedID int64
+}
+
+type BlockRepository interface {
+	Block(ctx context.Context, blockerID int64, blockedID int64) error
+	// IsBlocked reports whether either user has blocked the other.
+	IsBlocked(ctx context.Context, a int64, b int64) (bool, error)
+	GetBlocks(ctx context.Context) ([]Block, error)
+}
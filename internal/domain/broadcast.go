@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BroadcastSegment is the audience of a broadcast. Banned and unreachable
+// users are never included.
+type BroadcastSegment string
+
+const (
+	SegmentAll          BroadcastSegment = "all"
+	SegmentRegistered   BroadcastSegment = "registered"
+	SegmentUnregistered BroadcastSegment = "unregistered"
+	SegmentOptedOut     BroadcastSegment = "opted_out"
+	// SegmentMatched is everyone who has a meeting.
+	SegmentMatched BroadcastSegment = "matched"
+	// SegmentUnmatched is registered users without a meeting.
+	SegmentUnmatched BroadcastSegment = "unmatched"
+	// SegmentConfirmed is everyone who confirmed or arrived to their meeting.
+	SegmentConfirmed BroadcastSegment = "confirmed"
+)
+
+var BroadcastSegments = []BroadcastSegment{
+	SegmentAll,
+	SegmentRegistered,
+	SegmentUnregistered,
+	SegmentOptedOut,
+	SegmentMatched,
+	SegmentUnmatched,
+	SegmentConfirmed,
+}
+
+func (s BroadcastSegment) Valid() bool {
+	for _, segment := range BroadcastSegments {
+		if s == segment {
+			return true
+		}
+	}
+	return false
+}
+
+type BroadcastStatus string
+
+const (
+	BroadcastStatusDraft     BroadcastStatus = "draft"
+	BroadcastStatusScheduled BroadcastStatus = "scheduled"
+	BroadcastStatusSending   BroadcastStatus = "sending"
+	BroadcastStatusDone      BroadcastStatus = "done"
+	BroadcastStatusCancelled BroadcastStatus = "cancelled"
+)
+
+// Broadcast is an admin's message to a segment of users. The message itself
+// stays in the author's chat and is copied to every recipient.
+type Broadcast struct {
+	ID                int64
+	AuthorID          int64
+	Status            BroadcastStatus
+	Segment           BroadcastSegment
+	SourceChatID      int64
+	SourceMessageID   int
+	ScheduledAt       *time.Time
+	Total             int
+	ProgressMessageID int
+	CreatedAt         time.Time
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+}
+
+type BroadcastRepository interface {
+	CreateBroadcast(ctx context.Context, authorID int64) (*Broadcast, error)
+	GetBroadcast(ctx context.Context, id int64) (*Broadcast, error)
+	// GetDraft returns the author's latest broadcast that is not sent or scheduled yet.
+	GetDraft(ctx context.Context, authorID int64) (*Broadcast, error)
+	SetContent(ctx context.Context, id int64, chatID int64, messageID int) error
+	SetSegment(ctx context.Context, id int64, segment BroadcastSegment) error
+	CountRecipients(ctx context.Context, segment BroadcastSegment) (int, error)
+	// Schedule moves a draft to scheduled. Reports false if it is not a draft anymore.
+	Schedule(ctx context.Context, id int64, at time.Time) (bool, error)
+	// Cancel cancels a draft or scheduled broadcast. Reports false if it is already sending.
+	Cancel(ctx context.Context, id int64) (bool, error)
+	// Start queues the broadcast to every recipient of its segment and moves it
+	// to sending in one transaction. Reports false if it is not a draft or
+	// scheduled anymore.
+	Start(ctx context.Context, id int64) (bool, error)
+	GetDueScheduled(ctx context.Context) ([]Broadcast, error)
+	GetSending(ctx context.Context) ([]Broadcast, error)
+	GetBroadcastProgress(ctx context.Context, id int64) (OutboxStats, error)
+	SetProgressMessage(ctx context.Context, id int64, messageID int) error
+	Finish(ctx context.Context, id int64) error
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+type CheckInRepository interface {
+	StartCheckIn(ctx context.Context, meetingID int64, telegramID int64) error
+	GetPendingCheckIn(ctx context.Context, telegramID int64) (int64, error)
+	SaveLocation(ctx context.Context, meetingID int64, telegramID int64, lat, lon, distance float64, verified bool) error
+}
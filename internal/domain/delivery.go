@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DeliveryKind is the kind of notification in the delivery log.
+type DeliveryKind string
+
+const (
+	DeliveryRegistrationReminder DeliveryKind = "registration_reminder"
+	DeliveryInviteReminder       DeliveryKind = "invite_reminder"
+	DeliveryFeedbackRequest      DeliveryKind = "feedback_request"
+	DeliverySeeAgain             DeliveryKind = "see_again"
+)
+
+// MeetingReminderDelivery is the kind of the meeting reminder sent the given
+// time before the meeting.
+func MeetingReminderDelivery(before time.Duration) DeliveryKind {
+	return DeliveryKind(fmt.Sprintf("meeting_reminder:%d", int(before.Seconds())))
+}
+
+// DeliveryRepository is the log of notifications, keyed by kind, subject (the
+// meeting or user the notification is about) and recipient. A delivery is
+// claimed when its messages are queued, and the outbox marks it sent or failed
+// once it is done with them.
+type DeliveryRepository interface {
+	// Deliver claims the delivery and queues its messages to the outbox in one
+	// transaction, so they are queued once per attempt. Reports false, queueing
+	// nothing, if the delivery is claimed or sent already, or failed too many
+	// times; a delivery that failed fewer times is claimed again.
+	Deliver(ctx context.Context, kind DeliveryKind, subject int64, recipient int64, msgs []OutboxMessage) (bool, error)
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Incident is a participant's call for help during a meeting, kept for follow-up.
+type Incident struct {
+	ID             int64
+	MeetingID      int64
+	TelegramID     int64
+	CreatedAt      time.Time
+	AcknowledgedBy *int64
+	AcknowledgedAt *time.Time
+}
+
+type IncidentRepository interface {
+	CreateIncident(ctx context.Context, i *Incident) error
+	GetIncident(ctx context.Context, id int64) (*Incident, error)
+	// AcknowledgeIncident records the organizer who took the incident.
+	// Reports false if someone already has.
+	AcknowledgeIncident(ctx context.Context, id int64, organizerID int64) (bool, error)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Job is the run history of a scheduled notification job. LastSlotAt is the
+// scheduled time of the last run, so replicas don't run the same slot twice.
+type Job struct {
+	Name           string
+	Schedule       string
+	LastSlotAt     *time.Time
+	LastRunAt      *time.Time
+	LastFinishedAt *time.Time
+	LastError      string
+}
+
+type JobRepository interface {
+	// TryLock takes the job's advisory lock, held until release is called.
+	// Reports false if another replica holds it.
+	TryLock(ctx context.Context, name string) (release func(), ok bool, err error)
+	GetJob(ctx context.Context, name string) (*Job, error)
+	GetJobs(ctx context.Context) ([]Job, error)
+	StartRun(ctx context.Context, name string, schedule string, slot time.Time) error
+	FinishRun(ctx context.Context, name string, lastError string) error
+}
@@ -12,21 +12,80 @@ const (
 	StateConfirmed    ConfirmationState = "confirmed"
 	StateCancelled    ConfirmationState = "cancelled"
 	StateArrived      ConfirmationState = "arrived"
+	StateExpired      ConfirmationState = "expired"
 )
 
 type Meeting struct {
-	ID             int64
-	DillID         int64
-	DoeID          int64
-	PairScore      float64
-	IsFullmatch    bool
-	PlaceID        *int64
-	Time           *time.Time
-	DillState      ConfirmationState
-	DoeState       ConfirmationState
-	UsersNotified  bool
-	DillCantFind   bool
-	DoeCantFind    bool
+	ID            int64
+	DillID        int64
+	DoeID         int64
+	PairScore     float64
+	IsFullmatch   bool
+	PlaceID       *int64
+	Time          *time.Time
+	DillState     ConfirmationState
+	DoeState      ConfirmationState
+	DillCantFind  bool
+	DoeCantFind   bool
+	InvitedAt     *time.Time
+	Rematched     bool
+	SeeAgainAsked bool
+	DillSeeAgain  *bool
+	DoeSeeAgain   *bool
+
+	// Revision counts reschedules of the meeting.
+	Revision int
+}
+
+// Dropped reports whether the participant is out of the meeting,
+// either by cancelling it or by missing the confirmation deadline.
+func (s ConfirmationState) Dropped() bool {
+	return s == StateCancelled || s == StateExpired
+}
+
+// Attended reports whether the participant confirmed the meeting or checked in at it.
+func (s ConfirmationState) Attended() bool {
+	return s == StateConfirmed || s == StateArrived
+}
+
+// Confirmed reports whether both participants confirmed the meeting, which is
+// when they get it as a calendar event.
+func (m *Meeting) Confirmed() bool {
+	return m.DillState == StateConfirmed && m.DoeState == StateConfirmed
+}
+
+// Partner returns the other participant of the meeting.
+func (m *Meeting) Partner(telegramID int64) int64 {
+	if m.DillID == telegramID {
+		return m.DoeID
+	}
+	return m.DillID
+}
+
+// FeedbackRecipients returns the participants who should be asked to rate the
+// meeting: those who confirmed or arrived and whose partner did not drop out.
+// Whether they were asked already is kept in the delivery log.
+func (m *Meeting) FeedbackRecipients() []int64 {
+	var ids []int64
+	if m.DillState.Attended() && !m.DoeState.Dropped() {
+		ids = append(ids, m.DillID)
+	}
+	if m.DoeState.Attended() && !m.DillState.Dropped() {
+		ids = append(ids, m.DoeID)
+	}
+	return ids
+}
+
+// Stranded returns the participant left alone after their partner dropped
+// out of the meeting, or 0 if nobody is.
+func (m *Meeting) Stranded() int64 {
+	switch {
+	case m.DillState.Dropped() && !m.DoeState.Dropped():
+		return m.DoeID
+	case m.DoeState.Dropped() && !m.DillState.Dropped():
+		return m.DillID
+	}
+	return 0
 }
 
 type MeetingRepository interface {
@@ -35,14 +94,27 @@ type MeetingRepository interface {
 	GetRegularMeetings(ctx context.Context) ([]Meeting, error)
 	GetFullMeetings(ctx context.Context) ([]Meeting, error)
 	AssignPlaceAndTime(ctx context.Context, id int64, placeID int64, time time.Time) error
-	UpdateState(ctx context.Context, meetingID int64, isDill bool, state ConfirmationState) error
+	TransitionState(ctx context.Context, meetingID int64, isDill bool, from, to ConfirmationState) (*Meeting, error)
 	ClearMeetings(ctx context.Context) error
 	GetMeetingsStartingIn(ctx context.Context, interval time.Duration) ([]Meeting, error)
-	MarkNotified(ctx context.Context, meetingID int64) error
 	SetCantFind(ctx context.Context, meetingID int64, isDill bool) error
 	GetArrivedMeetingID(ctx context.Context, telegramID int64) (int64, error)
 	GetMeetingStats(ctx context.Context) (MeetingStats, error)
-	GetTelegramIDsForFeedbackRequest(ctx context.Context) ([]int64, error)
+	GetMeetingsForFeedbackRequest(ctx context.Context, after time.Duration) ([]Meeting, error)
+	MarkInvited(ctx context.Context, meetingID int64) error
+	ExpireUnconfirmed(ctx context.Context, deadline time.Duration) ([]Meeting, error)
+	GetStrandedMeetings(ctx context.Context) ([]Meeting, error)
+	// SaveRematch saves the new meetings, setting their IDs, and marks the
+	// stranded meetings rematched in one transaction.
+	SaveRematch(ctx context.Context, meetings []Meeting, rematched []int64) error
+	DeleteMeeting(ctx context.Context, meetingID int64) error
+	Reschedule(ctx context.Context, meetingID int64, placeID int64, time time.Time) error
+	GetMeetingsForSeeAgain(ctx context.Context, after time.Duration) ([]Meeting, error)
+	MarkSeeAgainAsked(ctx context.Context, meetingID int64) error
+	// SetSeeAgain stores the participant's answer unless they have answered
+	// already, and reports whether it was stored and whether it made the wish
+	// to meet again mutual.
+	SetSeeAgain(ctx context.Context, meetingID int64, isDill bool, answer bool) (stored bool, mutual bool, err error)
 }
 
 type MeetingStats struct {
@@ -50,4 +122,8 @@ type MeetingStats struct {
 	Confirmed uint
 	Cancelled uint
 	Pending   uint
+	// Answered counts meetings, archived ones included, where both participants said
+	// whether they want to meet again, Mutual the ones where both said yes.
+	Answered uint
+	Mutual   uint
 }
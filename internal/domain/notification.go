@@ -0,0 +1,27 @@
+package domain
+
+// NotificationCategory is a kind of non-urgent notification users can mute.
+// Messages about their meetings can't be muted.
+type NotificationCategory string
+
+const (
+	NotificationRegistrationReminder NotificationCategory = "registration_reminder"
+	NotificationInviteReminder       NotificationCategory = "invite_reminder"
+	NotificationFeedback             NotificationCategory = "feedback"
+)
+
+// NotificationCategories lists the categories in display order.
+var NotificationCategories = []NotificationCategory{
+	NotificationRegistrationReminder,
+	NotificationInviteReminder,
+	NotificationFeedback,
+}
+
+func (c NotificationCategory) Valid() bool {
+	for _, category := range NotificationCategories {
+		if category == c {
+			return true
+		}
+	}
+	return false
+}
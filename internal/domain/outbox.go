@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type OutboxKind string
+
+const (
+	// OutboxKindText is a plain text message.
+	OutboxKindText OutboxKind = "text"
+	// OutboxKindPlace is the place photo with the text as its caption.
+	OutboxKindPlace OutboxKind = "place"
+	// OutboxKindVenue is the place as a map pin.
+	OutboxKindVenue OutboxKind = "venue"
+	// OutboxKindCopy is a copy of the message FromMessageID in the chat FromChatID.
+	OutboxKindCopy OutboxKind = "copy"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	OutboxStatusFailed  OutboxStatus = "failed"
+)
+
+// OutboxMessage is an outgoing message waiting for or past delivery.
+// Markup holds the inline keyboard as Telegram JSON. If MessageKey is set,
+// the sent message's ID is stored as the user message of MeetingID under it.
+// DeliveryID links the message to the delivery whose outcome it settles.
+type OutboxMessage struct {
+	ID            int64
+	ChatID        int64
+	Kind          OutboxKind
+	Text          string
+	Markup        []byte
+	PlaceID       *int64
+	MeetingID     *int64
+	MessageKey    string
+	FromChatID    *int64
+	FromMessageID *int
+	BroadcastID   *int64
+	DeliveryID    *int64
+	Status        OutboxStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	MessageID     *int
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+type OutboxStats struct {
+	Pending uint
+	Sent    uint
+	Failed  uint
+}
+
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, m *OutboxMessage) error
+	// ClaimDue returns up to limit pending messages that are due, oldest first,
+	// and hides them from other claims for the lease, so a message whose
+	// delivery was interrupted is picked up again once it expires.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+	// MarkSent records the message as sent, and the delivery it belongs to as
+	// well once all of its messages are sent.
+	MarkSent(ctx context.Context, id int64, messageID int) error
+	// Retry puts the message back to the queue until at. Failed attempts are
+	// counted, while postponements because of rate limits are not.
+	Retry(ctx context.Context, id int64, at time.Time, failed bool, lastError string) error
+	// MarkFailed gives up on the message and fails the delivery it belongs to,
+	// so the delivery can be claimed again.
+	MarkFailed(ctx context.Context, id int64, lastError string) error
+	GetOutboxStats(ctx context.Context) (OutboxStats, error)
+}
@@ -1,17 +1,62 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 type Place struct {
 	ID          int64
 	Description string
 	PhotoURL    string
+	PhotoFileID string
 	Route       string
 	Quality     int
+	IsActive    bool
+	Latitude    *float64
+	Longitude   *float64
+	// Radius is the distance in meters from the place's coordinates within
+	// which a participant counts as arrived.
+	Radius int
+}
+
+func (p *Place) HasLocation() bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+type PlaceField string
+
+const (
+	PlaceFieldDescription PlaceField = "description"
+	PlaceFieldRoute       PlaceField = "route"
+	PlaceFieldQuality     PlaceField = "quality"
+	PlaceFieldPhoto       PlaceField = "photo"
+	PlaceFieldLocation    PlaceField = "location"
+	PlaceFieldRadius      PlaceField = "radius"
+)
+
+// PlaceEdit is an admin's pending change of a single place field, applied
+// with the next message they send.
+type PlaceEdit struct {
+	PlaceID int64
+	Field   PlaceField
 }
 
 type PlaceRepository interface {
-	SavePlace(ctx context.Context, description string) error
+	SavePlace(ctx context.Context, p *Place) error
+	UpdatePlace(ctx context.Context, p *Place) error
+	SetPlaceActive(ctx context.Context, placeID int64, active bool) error
+	SetPhotoFileID(ctx context.Context, placeID int64, fileID string) error
 	GetAllPlaces(ctx context.Context) ([]Place, error)
+	GetActivePlaces(ctx context.Context) ([]Place, error)
 	GetPlace(ctx context.Context, placeID int64) (*Place, error)
+	SetPendingEdit(ctx context.Context, telegramID int64, e PlaceEdit) error
+	GetPendingEdit(ctx context.Context, telegramID int64) (*PlaceEdit, error)
+	ClearPendingEdit(ctx context.Context, telegramID int64) error
+}
+
+// PhotoStore keeps place photos addressed by the key stored in Place.PhotoURL.
+type PhotoStore interface {
+	GetPhoto(ctx context.Context, key string) (io.ReadCloser, error)
+	PutPhoto(ctx context.Context, key string, r io.Reader, size int64) error
 }
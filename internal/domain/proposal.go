@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ProposalStatus string
+
+const (
+	ProposalPending  ProposalStatus = "pending"
+	ProposalAccepted ProposalStatus = "accepted"
+	ProposalDeclined ProposalStatus = "declined"
+)
+
+// Proposal is a request from one participant to move the meeting to another time.
+type Proposal struct {
+	ID         int64
+	MeetingID  int64
+	ProposerID int64
+	Time       time.Time
+	Status     ProposalStatus
+}
+
+type ProposalRepository interface {
+	SaveProposal(ctx context.Context, p *Proposal) error
+	GetProposal(ctx context.Context, id int64) (*Proposal, error)
+	ResolveProposal(ctx context.Context, id int64, status ProposalStatus) (bool, error)
+	DeclinePendingProposals(ctx context.Context, meetingID int64) error
+}
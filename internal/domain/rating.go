@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type RatingTag string
+
+const (
+	RatingTagGreatConversation RatingTag = "great_conversation"
+	RatingTagPartnerNoShow     RatingTag = "partner_no_show"
+	RatingTagPartnerLate       RatingTag = "partner_late"
+	RatingTagBadPlace          RatingTag = "bad_place"
+)
+
+// RatingTags lists the tags a participant can attach to their rating, in display order.
+var RatingTags = []RatingTag{
+	RatingTagGreatConversation,
+	RatingTagPartnerNoShow,
+	RatingTagPartnerLate,
+	RatingTagBadPlace,
+}
+
+func (t RatingTag) Valid() bool {
+	for _, tag := range RatingTags {
+		if tag == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Rating is a participant's rating of a meeting. Meeting IDs stay valid after
+// meetings are archived, so ratings are kept across events.
+type Rating struct {
+	MeetingID int64
+	RaterID   int64
+	Stars     int
+	Tags      []RatingTag
+	Comment   string
+	CreatedAt time.Time
+}
+
+func (r *Rating) HasTag(tag RatingTag) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+type RatingStats struct {
+	Count   uint
+	Average float64
+	Tags    map[RatingTag]uint
+}
+
+type RatingRepository interface {
+	SaveStars(ctx context.Context, meetingID int64, raterID int64, stars int) error
+	GetRating(ctx context.Context, meetingID int64, raterID int64) (*Rating, error)
+	SetTags(ctx context.Context, meetingID int64, raterID int64, tags []RatingTag) error
+	// SetLatestComment attaches the comment to the rater's most recent rating and
+	// reports whether they have one.
+	SetLatestComment(ctx context.Context, raterID int64, comment string) (bool, error)
+	GetRatingStats(ctx context.Context) (RatingStats, error)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RelayMessage is a message anonymously relayed between meeting partners.
+// Relayed messages are kept for moderation.
+type RelayMessage struct {
+	ID          int64
+	MeetingID   int64
+	SenderID    int64
+	RecipientID int64
+	Text        string
+	CreatedAt   time.Time
+}
+
+type RelayRepository interface {
+	StartSession(ctx context.Context, telegramID int64, meetingID int64) error
+	GetSession(ctx context.Context, telegramID int64) (int64, error)
+	EndSession(ctx context.Context, telegramID int64) error
+	SaveMessage(ctx context.Context, m *RelayMessage) error
+	CountMessagesSince(ctx context.Context, meetingID int64, since time.Time) (int, error)
+	GetMessages(ctx context.Context, meetingID int64, limit int) ([]RelayMessage, error)
+	GetUserMessages(ctx context.Context, telegramID int64, limit int) ([]RelayMessage, error)
+}
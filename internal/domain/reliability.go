@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Reliability sums up how a user behaved in their past meetings,
+// both in the current event and in the archived ones.
+type Reliability struct {
+	Arrived int
+	// NoShows counts meetings the user confirmed but never arrived at.
+	NoShows int
+	// LateCancels counts meetings the user cancelled shortly before they started.
+	LateCancels int
+	// Reports counts meetings where the partner arrived and could not find the user,
+	// and reports against the user that moderators resolved with a warning or a ban.
+	Reports int
+}
+
+// Penalty scores the user's unreliability. A no-show weighs double,
+// since the partner came to the meeting for nothing.
+func (r Reliability) Penalty() int {
+	return 2*r.NoShows + r.LateCancels + r.Reports
+}
+
+type ReliabilityRepository interface {
+	// GetReliability treats a confirmed meeting as a no-show noShowAfter it started,
+	// and a cancellation as late if it happened less than lateCancelWithin before the meeting.
+	GetReliability(ctx context.Context, telegramID int64, noShowAfter, lateCancelWithin time.Duration) (Reliability, error)
+	// GetAllReliability is like GetReliability for every user who has had a meeting or an upheld report.
+	GetAllReliability(ctx context.Context, noShowAfter, lateCancelWithin time.Duration) (map[int64]Reliability, error)
+}
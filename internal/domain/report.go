@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ReportReason string
+
+const (
+	ReportReasonHarassment  ReportReason = "harassment"
+	ReportReasonOffensive   ReportReason = "offensive"
+	ReportReasonFakeProfile ReportReason = "fake_profile"
+	ReportReasonNoShow      ReportReason = "no_show"
+	ReportReasonOther       ReportReason = "other"
+)
+
+// ReportReasons lists the reasons a participant can report their partner for, in display order.
+var ReportReasons = []ReportReason{
+	ReportReasonHarassment,
+	ReportReasonOffensive,
+	ReportReasonFakeProfile,
+	ReportReasonNoShow,
+	ReportReasonOther,
+}
+
+func (r ReportReason) Valid() bool {
+	for _, reason := range ReportReasons {
+		if reason == r {
+			return true
+		}
+	}
+	return false
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusPending   ReportStatus = "pending"
+	ReportStatusDismissed ReportStatus = "dismissed"
+	ReportStatusWarned    ReportStatus = "warned"
+	ReportStatusBanned    ReportStatus = "banned"
+)
+
+// ReportResolutions lists the statuses an admin can resolve a report with.
+var ReportResolutions = []ReportStatus{
+	ReportStatusDismissed,
+	ReportStatusWarned,
+	ReportStatusBanned,
+}
+
+type Report struct {
+	ID         int64
+	MeetingID  int64
+	ReporterID int64
+	ReportedID int64
+	Reason     ReportReason
+	Status     ReportStatus
+	ResolvedBy *int64
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+type ReportRepository interface {
+	// CreateReport saves the report. Reports false if the reporter has already
+	// reported this meeting.
+	CreateReport(ctx context.Context, r *Report) (bool, error)
+	GetReport(ctx context.Context, id int64) (*Report, error)
+	GetNextPendingReport(ctx context.Context) (*Report, error)
+	CountPendingReports(ctx context.Context) (int, error)
+	CountReportsAgainst(ctx context.Context, telegramID int64) (int, error)
+	// ResolveReport resolves a pending report. Reports false if it is already resolved.
+	ResolveReport(ctx context.Context, id int64, status ReportStatus, adminID int64) (bool, error)
+}
@@ -9,4 +9,6 @@ type Statistics struct {
 	MaleCount        uint
 	FemaleCount      uint
 	Meetings         MeetingStats
+	Ratings          RatingStats
+	Outbox           OutboxStats
 }
@@ -42,15 +42,20 @@ func SetToBinary(selected map[string]bool) string {
 	return string(result)
 }
 
-func PickRandomTime(timeIntersection string) string {
+// AvailableHours returns the starting hours of meetings that fit into the time ranges.
+func AvailableHours(timeRanges string) []int {
 	var hours []int
-	for i, bit := range timeIntersection {
+	for i, bit := range timeRanges {
 		if bit == '1' && i < len(TimeRanges) {
 			startHour := 10 + i*2
 			hours = append(hours, startHour, startHour+1)
 		}
 	}
+	return hours
+}
 
+func PickRandomTime(timeIntersection string) string {
+	hours := AvailableHours(timeIntersection)
 	if len(hours) == 0 {
 		return "12:00"
 	}
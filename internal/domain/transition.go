@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// transitions lists the states a participant can move to from each state.
+// Rescheduling a meeting is the only way back to StateNotConfirmed.
+var transitions = map[ConfirmationState][]ConfirmationState{
+	StateNotConfirmed: {StateConfirmed, StateCancelled, StateExpired},
+	StateConfirmed:    {StateCancelled, StateArrived},
+	StateCancelled:    {},
+	StateExpired:      {},
+	StateArrived:      {},
+}
+
+// TransitionError is returned when a participant's state cannot be changed,
+// either because the transition is illegal or because the state has changed
+// concurrently.
+type TransitionError struct {
+	From ConfirmationState
+	To   ConfirmationState
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("illegal meeting state transition from %s to %s", e.From, e.To)
+}
+
+func (s ConfirmationState) CanTransitionTo(to ConfirmationState) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns a *TransitionError if the participant cannot move from one state to the other.
+func CheckTransition(from, to ConfirmationState) error {
+	if !from.CanTransitionTo(to) {
+		return &TransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+func IsTransitionError(err error) bool {
+	var terr *TransitionError
+	return errors.As(err, &terr)
+}
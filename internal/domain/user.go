@@ -10,36 +10,40 @@ import (
 type UserState string
 
 const (
-	UserStateStart              UserState = "start"
-	UserStateAwaitingSex        UserState = "awaiting_sex"
-	UserStateAwaitingAbout      UserState = "awaiting_about"
-	UserStateAwaitingTime       UserState = "awaiting_time"
-	UserStateAwaitingSupport    UserState = "awaiting_support"
-	UserStateAwaitingAppearance UserState = "awaiting_appearance"
-	UserStateAwaitingFeedback   UserState = "awaiting_feedback"
-	UserStateCompleted          UserState = "completed"
+	UserStateStart                 UserState = "start"
+	UserStateAwaitingSex           UserState = "awaiting_sex"
+	UserStateAwaitingAbout         UserState = "awaiting_about"
+	UserStateAwaitingAboutDetails  UserState = "awaiting_about_details"
+	UserStateAwaitingTime          UserState = "awaiting_time"
+	UserStateAwaitingSupport       UserState = "awaiting_support"
+	UserStateAwaitingAppearance    UserState = "awaiting_appearance"
+	UserStateAwaitingFeedback      UserState = "awaiting_feedback"
+	UserStateAwaitingPlaceEdit     UserState = "awaiting_place_edit"
+	UserStateChatting              UserState = "chatting"
+	UserStateAwaitingLocation      UserState = "awaiting_location"
+	UserStateAwaitingBroadcast     UserState = "awaiting_broadcast"
+	UserStateAwaitingBroadcastTime UserState = "awaiting_broadcast_time"
+	UserStateCompleted             UserState = "completed"
 )
 
 type User struct {
-	TelegramID           int64
-	Username             string
-	FirstName            string
-	LastName             string
-	IsBot                bool
-	LanguageCode         string
-	IsPremium            bool
-	Sex                  string
-	About                string
-	State                UserState
-	RegistrationNotified bool
-	InviteNotified       bool
-	TimeRanges           string
-	IsAdmin              bool
-	OptedOut             bool
-	IsRegistered         bool
-	ReferralCode         string
-	ReferrerID           *int64
-	CreatedAt            time.Time
+	TelegramID   int64
+	Username     string
+	FirstName    string
+	LastName     string
+	IsBot        bool
+	LanguageCode string
+	IsPremium    bool
+	Sex          string
+	About        string
+	State        UserState
+	TimeRanges   string
+	IsAdmin      bool
+	OptedOut     bool
+	IsRegistered bool
+	ReferralCode string
+	ReferrerID   *int64
+	CreatedAt    time.Time
 }
 
 type ReferralLeaderboardEntry struct {
@@ -54,13 +58,29 @@ type UserRepository interface {
 	GetUser(ctx context.Context, telegramID int64) (*User, error)
 	GetUserByUsername(ctx context.Context, username string) (*User, error)
 	GetUserState(ctx context.Context, telegramID int64) (UserState, error)
+	// SetUserState moves the user to the state, abandoning a profile edit.
 	SetUserState(ctx context.Context, telegramID int64, state UserState) error
+	// BeginEdit moves the user to the state of a single profile step and
+	// remembers the state to return to once the step is done.
+	BeginEdit(ctx context.Context, telegramID int64, state UserState) error
+	// SetStep moves the user to another step of the registration or profile
+	// edit they are going through, keeping the state BeginEdit remembered.
+	SetStep(ctx context.Context, telegramID int64, state UserState) error
+	// FinishEdit returns the user to the state remembered by BeginEdit and
+	// reports false if they were not editing a single step.
+	FinishEdit(ctx context.Context, telegramID int64) (bool, error)
 	SetUserSex(ctx context.Context, telegramID int64, sex string) error
 	SetUserAbout(ctx context.Context, telegramID int64, about string) error
 	GetTimeRanges(ctx context.Context, telegramID int64) (string, error)
 	SaveTimeRanges(ctx context.Context, telegramID int64, timeRanges string) error
 	IsAdmin(ctx context.Context, telegramID int64) (bool, error)
 	SetAdmin(ctx context.Context, telegramID int64, isAdmin bool) error
+	IsBanned(ctx context.Context, telegramID int64) (bool, error)
+	SetBanned(ctx context.Context, telegramID int64, banned bool) error
+	// SetUnreachable flags the user who can't get messages from the bot, e.g.
+	// because they blocked it, and reports whether the flag has changed.
+	SetUnreachable(ctx context.Context, telegramID int64, unreachable bool) (bool, error)
+	IsUnreachable(ctx context.Context, telegramID int64) (bool, error)
 	GetVerifiedUsers(ctx context.Context) ([]User, error)
 	GetUserUsername(ctx context.Context, telegramID int64) (string, error)
 	GetAdmins(ctx context.Context) ([]User, error)
@@ -68,15 +88,16 @@ type UserRepository interface {
 	SetReferralCode(ctx context.Context, telegramID int64, code string) error
 	SetReferrer(ctx context.Context, telegramID int64, referrerID int64) error
 	GetReferralLeaderboard(ctx context.Context) ([]ReferralLeaderboardEntry, error)
-	MarkNotified(ctx context.Context, telegramID int64) error
 	GetNotCompleted(ctx context.Context, interval time.Duration) ([]User, error)
 	GetForInviteReminder(ctx context.Context, interval time.Duration) ([]User, error)
-	MarkInviteNotified(ctx context.Context, telegramID int64) error
 	SetOptedOut(ctx context.Context, telegramID int64, optedOut bool) error
 	GetLastRegisteredCount(ctx context.Context) (daily uint, weekly uint, err error)
 	GetSexCounts(ctx context.Context) (males uint, females uint, err error)
 	GetUserCounts(ctx context.Context) (total uint, registered uint, optedOut uint, err error)
 	GetUnregisteredUsers(ctx context.Context) ([]User, error)
+	GetMutedCategories(ctx context.Context, telegramID int64) ([]NotificationCategory, error)
+	IsMuted(ctx context.Context, telegramID int64, category NotificationCategory) (bool, error)
+	SetMuted(ctx context.Context, telegramID int64, category NotificationCategory, muted bool) error
 }
 
 const (
@@ -0,0 +1,68 @@
+package disk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store keeps photos as plain files in a local directory
+type Store struct {
+	dir string
+}
+
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create photos dir: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) GetPhoto(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *Store) PutPhoto(ctx context.Context, key string, r io.Reader, size int64) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create photo dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write photo: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close photo: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// path resolves the key inside the store directory, rejecting keys that escape it
+func (s *Store) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(s.dir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid photo key: %q", key)
+	}
+	return path, nil
+}
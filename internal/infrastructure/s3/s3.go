@@ -39,3 +39,8 @@ func (c *Client) GetPhoto(ctx context.Context, key string) (io.ReadCloser, error
 	}
 	return obj, nil
 }
+
+func (c *Client) PutPhoto(ctx context.Context, key string, r io.Reader, size int64) error {
+	_, err := c.client.PutObject(ctx, c.bucket, key, r, size, minio.PutObjectOptions{ContentType: "image/jpeg"})
+	return err
+}
@@ -0,0 +1,203 @@
+// Package cron parses standard five-field cron specs: minute, hour, day of
+// month, month and day of week. Fields accept *, numbers, ranges (1-5),
+// steps (*/15, 1-30/5) and comma-separated lists of those.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLookahead bounds the search for the next run, so a spec that never
+// matches (e.g. February 30th) does not loop forever.
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+type field struct {
+	min, max int
+}
+
+var fields = [5]field{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week, sunday is 0
+}
+
+type Schedule struct {
+	spec   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domAny and dowAny record day fields that match any day, e.g. * or */1:
+	// if both are restricted, a day matching either of them matches, as in the
+	// classic cron.
+	domAny bool
+	dowAny bool
+}
+
+// Parse parses a five-field cron spec.
+func Parse(spec string) (*Schedule, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron: expected %d fields, got %d in %q", len(fields), len(parts), spec)
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron: %q: %w", spec, err)
+		}
+		bits[i] = b
+	}
+
+	// 7 is sunday too
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Schedule{
+		spec:   spec,
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: full(bits[2], fields[2]),
+		dowAny: full(bits[4], fields[4]),
+	}, nil
+}
+
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time after t matching the schedule, in t's location.
+// It returns the zero time if there is none within the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxLookahead)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func parseField(s string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		b, err := parsePart(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func parsePart(s string, f field) (uint64, error) {
+	rng, step, hasStep := strings.Cut(s, "/")
+
+	lo, hi := f.min, f.max
+	// day of week accepts 7 for sunday
+	if f.max == 6 {
+		hi = 7
+	}
+
+	if rng != "*" {
+		from, to, isRange := strings.Cut(rng, "-")
+
+		var err error
+		if lo, err = parseNumber(from, f); err != nil {
+			return 0, err
+		}
+		hi = lo
+		if isRange {
+			if hi, err = parseNumber(to, f); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			hi = f.max
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q", rng)
+		}
+	}
+
+	n := 1
+	if hasStep {
+		var err error
+		n, err = strconv.Atoi(step)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step %q", step)
+		}
+	}
+
+	var bits uint64
+	for v := lo; v <= hi; v += n {
+		bits |= 1 << v
+	}
+	return bits, nil
+}
+
+func parseNumber(s string, f field) (int, error) {
+	max := f.max
+	if max == 6 {
+		max = 7
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", s, f.min, max)
+	}
+	return v, nil
+}
+
+// full reports whether bits has every value of the field.
+func full(bits uint64, f field) bool {
+	mask := uint64(1)<<(f.max+1) - uint64(1)<<f.min
+	return bits&mask == mask
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<v) != 0
+}
@@ -0,0 +1,102 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"empty", ""},
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "* 24 * * *"},
+		{"day of month zero", "* * 0 * *"},
+		{"month out of range", "* * * 13 *"},
+		{"day of week out of range", "* * * * 8"},
+		{"negative value", "-1 * * * *"},
+		{"open range", "1- * * * *"},
+		{"reversed range", "5-1 * * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"bad step", "*/x * * * *"},
+		{"not a number", "a * * * *"},
+		{"empty list item", "1,,2 * * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s, err := Parse(tt.spec); err == nil {
+				t.Errorf("Parse(%q) = %v, want error", tt.spec, s)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a monday
+	from := time.Date(2026, 10, 19, 10, 7, 30, 0, time.UTC)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", from, at(10, 19, 10, 8)},
+		{"strictly after", "*/15 * * * *", at(10, 19, 10, 15), at(10, 19, 10, 30)},
+		{"minute step", "*/15 * * * *", from, at(10, 19, 10, 15)},
+		{"list", "5,50 * * * *", from, at(10, 19, 10, 50)},
+		{"hour step", "0 */6 * * *", from, at(10, 19, 12, 0)},
+		{"range with step", "30 9-17/4 * * *", from, at(10, 19, 13, 30)},
+		{"start with step", "0 20/2 * * *", from, at(10, 19, 20, 0)},
+		{"range", "0 0 * * 2-4", from, at(10, 20, 0, 0)},
+		{"sunday as 0", "0 0 * * 0", from, at(10, 25, 0, 0)},
+		{"sunday as 7", "0 0 * * 7", from, at(10, 25, 0, 0)},
+		{"day of month", "0 12 1 * *", from, at(11, 1, 12, 0)},
+		{"next year", "0 0 1 1 *", from, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", from, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// with both day fields restricted, either one matches
+		{"day of month or week: week first", "0 12 1 * 3", from, at(10, 21, 12, 0)},
+		{"day of month or week: month first", "0 12 20 * 5", from, at(10, 20, 12, 0)},
+		// a day field covering every day counts as unrestricted
+		{"day of month */1", "0 12 */1 * 5", from, at(10, 23, 12, 0)},
+		{"day of week */1", "0 12 1 * */1", from, at(11, 1, 12, 0)},
+		{"day of week 0-7", "0 12 1 * 0-7", from, at(11, 1, 12, 0)},
+		{"february 30th", "0 0 30 2 *", from, time.Time{}},
+		{"april 31st", "0 0 31 4 *", from, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextKeepsLocation(t *testing.T) {
+	loc := time.FixedZone("Europe/Samara", 4*60*60)
+
+	s, err := Parse("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.Next(time.Date(2026, 10, 19, 10, 0, 0, 0, loc))
+	want := time.Date(2026, 10, 20, 9, 0, 0, 0, loc)
+	if !got.Equal(want) || got.Location() != loc {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+// Package ics writes single-event iCalendar files as specified by RFC 5545.
+package ics
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type Method string
+
+const (
+	MethodPublish Method = "PUBLISH"
+	MethodCancel  Method = "CANCEL"
+)
+
+const (
+	prodID = "-//kypidbot//kypidbot//RU"
+	// lineLimit is the maximum length of a content line in octets, excluding the line break.
+	lineLimit = 75
+	utcLayout = "20060102T150405Z"
+)
+
+type Event struct {
+	// UID identifies the event across updates and cancellations.
+	UID string
+	// Sequence is the revision of the event. A cancellation must have a
+	// higher sequence than the event it cancels.
+	Sequence    int
+	Start       time.Time
+	Duration    time.Duration
+	Summary     string
+	Location    string
+	Description string
+	Latitude    *float64
+	Longitude   *float64
+	// Alarm is how long before the start a reminder is shown, 0 for no reminder.
+	Alarm time.Duration
+}
+
+// Marshal encodes the event into an iCalendar object. With MethodCancel the
+// event is marked as cancelled, so calendar apps remove it.
+func Marshal(method Method, e Event, now time.Time) []byte {
+	var w writer
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", prodID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", string(method))
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", escape(e.UID))
+	w.line("SEQUENCE", fmt.Sprintf("%d", e.Sequence))
+	w.line("DTSTAMP", now.UTC().Format(utcLayout))
+	w.line("DTSTART", e.Start.UTC().Format(utcLayout))
+	w.line("DTEND", e.Start.Add(e.Duration).UTC().Format(utcLayout))
+	w.line("SUMMARY", escape(e.Summary))
+	if e.Location != "" {
+		w.line("LOCATION", escape(e.Location))
+	}
+	if e.Description != "" {
+		w.line("DESCRIPTION", escape(e.Description))
+	}
+	if e.Latitude != nil && e.Longitude != nil {
+		w.line("GEO", fmt.Sprintf("%.6f;%.6f", *e.Latitude, *e.Longitude))
+	}
+
+	if method == MethodCancel {
+		w.line("STATUS", "CANCELLED")
+	} else {
+		w.line("STATUS", "CONFIRMED")
+		if e.Alarm > 0 {
+			w.line("BEGIN", "VALARM")
+			w.line("ACTION", "DISPLAY")
+			w.line("DESCRIPTION", escape(e.Summary))
+			w.line("TRIGGER", "-"+duration(e.Alarm))
+			w.line("END", "VALARM")
+		}
+	}
+	w.line("END", "VEVENT")
+
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line terminated by CRLF, folding it into continuation
+// lines that start with a space so no line exceeds lineLimit octets. Lines are
+// only folded between UTF-8 characters.
+func (w *writer) line(name, value string) {
+	s := name + ":" + value
+
+	limit := lineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// the leading space of a continuation line counts towards the limit
+		limit = lineLimit - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// duration formats a positive duration as a DURATION value, e.g. PT1H30M.
+func duration(d time.Duration) string {
+	d = d.Round(time.Second)
+
+	var sb strings.Builder
+	sb.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&sb, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&sb, "%dM", m)
+		d -= m * time.Minute
+	}
+	if s := d / time.Second; s > 0 || sb.Len() == 2 {
+		fmt.Fprintf(&sb, "%dS", s)
+	}
+	return sb.String()
+}
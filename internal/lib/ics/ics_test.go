@@ -0,0 +1,223 @@
+package ics
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	samara = time.FixedZone("Europe/Samara", 4*60*60)
+	now    = time.Date(2026, 2, 10, 12, 0, 0, 0, samara)
+	start  = time.Date(2026, 2, 14, 18, 30, 0, 0, samara)
+)
+
+func event() Event {
+	return Event{
+		UID:      "meeting-1@kypidbot",
+		Start:    start,
+		Duration: time.Hour,
+		Summary:  "Свидание",
+		Alarm:    time.Hour,
+	}
+}
+
+// unfold splits the object into content lines, joining continuation lines.
+func unfold(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	s := string(data)
+	if !strings.HasSuffix(s, "\r\n") {
+		t.Fatalf("object does not end with CRLF: %q", s)
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func property(t *testing.T, lines []string, name string) string {
+	t.Helper()
+
+	for _, line := range lines {
+		if value, ok := strings.CutPrefix(line, name+":"); ok {
+			return value
+		}
+	}
+	t.Fatalf("no %s property in %q", name, lines)
+	return ""
+}
+
+func sequence(t *testing.T, lines []string) int {
+	t.Helper()
+
+	n, err := strconv.Atoi(property(t, lines, "SEQUENCE"))
+	if err != nil {
+		t.Fatalf("parse SEQUENCE: %v", err)
+	}
+	return n
+}
+
+func TestMarshalLineEndings(t *testing.T) {
+	data := string(Marshal(MethodPublish, event(), now))
+
+	if strings.Contains(strings.ReplaceAll(data, "\r\n", ""), "\n") {
+		t.Errorf("bare LF in %q", data)
+	}
+	if strings.Contains(strings.ReplaceAll(data, "\r\n", ""), "\r") {
+		t.Errorf("bare CR in %q", data)
+	}
+	if !strings.HasPrefix(data, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(data, "END:VCALENDAR\r\n") {
+		t.Errorf("object is not a VCALENDAR: %q", data)
+	}
+}
+
+func TestMarshalFolding(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+	}{
+		{"ascii", strings.Repeat("a", 200)},
+		{"two-byte", strings.Repeat("я", 100)},
+		{"three-byte", strings.Repeat("€", 70)},
+		{"four-byte", strings.Repeat("💕", 50)},
+		{"mixed", "a" + strings.Repeat("я💕", 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event()
+			e.Summary = tt.summary
+			data := string(Marshal(MethodPublish, e, now))
+
+			folded := false
+			for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+				if len(line) > lineLimit {
+					t.Errorf("line is %d octets long: %q", len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line splits a UTF-8 sequence: %q", line)
+				}
+				if strings.HasPrefix(line, " ") {
+					folded = true
+				}
+			}
+			if !folded {
+				t.Errorf("long line was not folded: %q", data)
+			}
+
+			if got := property(t, unfold(t, []byte(data)), "SUMMARY"); got != tt.summary {
+				t.Errorf("SUMMARY = %q, want %q", got, tt.summary)
+			}
+		})
+	}
+}
+
+func TestMarshalEscaping(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"backslash", `a\b`, `a\\b`},
+		{"semicolon", "a;b", `a\;b`},
+		{"comma", "a,b", `a\,b`},
+		{"newline", "a\nb", `a\nb`},
+		{"crlf", "a\r\nb", `a\nb`},
+		{"all", "\\;,\n", `\\\;\,\n`},
+		{"plain", "Кофейня у вокзала", "Кофейня у вокзала"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event()
+			e.Location = tt.text
+			lines := unfold(t, Marshal(MethodPublish, e, now))
+
+			if got := property(t, lines, "LOCATION"); got != tt.want {
+				t.Errorf("LOCATION = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalTimesInUTC(t *testing.T) {
+	lines := unfold(t, Marshal(MethodPublish, event(), now))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"DTSTAMP", "20260210T080000Z"},
+		{"DTSTART", "20260214T143000Z"},
+		{"DTEND", "20260214T153000Z"},
+	}
+	for _, tt := range tests {
+		if got := property(t, lines, tt.name); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMarshalAlarm(t *testing.T) {
+	tests := []struct {
+		alarm time.Duration
+		want  string
+	}{
+		{time.Hour, "-PT1H"},
+		{90 * time.Minute, "-PT1H30M"},
+		{15 * time.Minute, "-PT15M"},
+		{30 * time.Second, "-PT30S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			e := event()
+			e.Alarm = tt.alarm
+			lines := unfold(t, Marshal(MethodPublish, e, now))
+
+			if got := property(t, lines, "TRIGGER"); got != tt.want {
+				t.Errorf("TRIGGER = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		e := event()
+		e.Alarm = 0
+		if data := string(Marshal(MethodPublish, e, now)); strings.Contains(data, "VALARM") {
+			t.Errorf("unexpected alarm in %q", data)
+		}
+	})
+}
+
+func TestMarshalCancel(t *testing.T) {
+	published := event()
+	cancelled := event()
+	cancelled.Sequence = published.Sequence + 1
+
+	pub := unfold(t, Marshal(MethodPublish, published, now))
+	cancel := unfold(t, Marshal(MethodCancel, cancelled, now))
+
+	if got := property(t, pub, "METHOD"); got != "PUBLISH" {
+		t.Errorf("published METHOD = %q, want PUBLISH", got)
+	}
+	if got := property(t, pub, "STATUS"); got != "CONFIRMED" {
+		t.Errorf("published STATUS = %q, want CONFIRMED", got)
+	}
+
+	if got := property(t, cancel, "METHOD"); got != "CANCEL" {
+		t.Errorf("METHOD = %q, want CANCEL", got)
+	}
+	if got := property(t, cancel, "STATUS"); got != "CANCELLED" {
+		t.Errorf("STATUS = %q, want CANCELLED", got)
+	}
+	if got, want := property(t, cancel, "UID"), property(t, pub, "UID"); got != want {
+		t.Errorf("UID = %q, want %q", got, want)
+	}
+	if got, want := sequence(t, cancel), sequence(t, pub); got <= want {
+		t.Errorf("SEQUENCE = %d, want higher than %d", got, want)
+	}
+	if strings.Contains(strings.Join(cancel, "\n"), "VALARM") {
+		t.Errorf("cancellation carries an alarm: %q", cancel)
+	}
+}
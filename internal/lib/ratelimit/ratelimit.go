@@ -0,0 +1,100 @@
+// Package ratelimit spaces out outgoing requests both globally and per key,
+// following the Telegram Bot API limits: about 30 messages per second in
+// total and one message per second to the same chat.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneThreshold is the number of tracked keys after which keys that are
+// ready again are forgotten.
+const pruneThreshold = 1024
+
+type Limiter struct {
+	mu sync.Mutex
+	// spacing is the minimal time between any two requests.
+	spacing time.Duration
+	// keyInterval is the minimal time between two requests with the same key.
+	keyInterval time.Duration
+	next        time.Time
+	keys        map[int64]time.Time
+}
+
+// New creates a limiter allowing rate requests per second in total and one
+// request per keyInterval for each key. A non-positive rate disables the
+// global limit.
+func New(rate int, keyInterval time.Duration) *Limiter {
+	var spacing time.Duration
+	if rate > 0 {
+		spacing = time.Second / time.Duration(rate)
+	}
+
+	return &Limiter{
+		spacing:     spacing,
+		keyInterval: keyInterval,
+		keys:        make(map[int64]time.Time),
+	}
+}
+
+// Reserve takes the key's slot and returns 0 if the key is ready, or how
+// long is left until it is, without taking anything.
+func (l *Limiter) Reserve(key int64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if at, ok := l.keys[key]; ok && at.After(now) {
+		return at.Sub(now)
+	}
+
+	if len(l.keys) >= pruneThreshold {
+		for k, at := range l.keys {
+			if !at.After(now) {
+				delete(l.keys, k)
+			}
+		}
+	}
+
+	l.keys[key] = now.Add(l.keyInterval)
+	return 0
+}
+
+// Wait blocks until the next global slot, or until ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.spacing)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pause holds back all requests for d, e.g. when Telegram asked to retry later.
+func (l *Limiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if at := time.Now().Add(d); at.After(l.next) {
+		l.next = at
+	}
+}
@@ -14,6 +14,15 @@ type MatchUser struct {
 	Sex        string
 	About      string
 	TimeRanges string
+	// Penalty lowers the user's scores in the assignment, so unreliable
+	// users are the first to be left without a pair.
+	Penalty float64
+	// Blocked holds the indices of users this one must never be paired with.
+	Blocked map[int]bool
+}
+
+func blocked(a, b MatchUser) bool {
+	return a.Blocked[b.Index] || b.Blocked[a.Index]
 }
 
 type MatchPair struct {
@@ -70,7 +79,7 @@ func Match(users []MatchUser, ollama *ollama.Client) ([]MatchPair, []FullMatch,
 			}
 
 			a, b := users[i], users[j]
-			if a.Sex == b.Sex {
+			if a.Sex == b.Sex || blocked(a, b) {
 				continue
 			}
 
@@ -136,7 +145,7 @@ func Match(users []MatchUser, ollama *ollama.Client) ([]MatchPair, []FullMatch,
 			mi, fj := males[i], females[j]
 			pairTime := calculateTimeIntersection(users[mi].TimeRanges, users[fj].TimeRanges)
 
-			if !hasTimeOverlap(pairTime) {
+			if !hasTimeOverlap(pairTime) || blocked(users[mi], users[fj]) {
 				scoreMatrix[i][j] = -1e9
 				continue
 			}
@@ -148,6 +157,7 @@ func Match(users []MatchUser, ollama *ollama.Client) ([]MatchPair, []FullMatch,
 			if aWantsB || bWantsA {
 				score += 0.3
 			}
+			score -= users[mi].Penalty + users[fj].Penalty
 
 			scoreMatrix[i][j] = score
 		}
@@ -161,7 +171,7 @@ func Match(users []MatchUser, ollama *ollama.Client) ([]MatchPair, []FullMatch,
 		}
 		mi, fj := males[i], females[j]
 		pairTime := calculateTimeIntersection(users[mi].TimeRanges, users[fj].TimeRanges)
-		if !hasTimeOverlap(pairTime) {
+		if !hasTimeOverlap(pairTime) || blocked(users[mi], users[fj]) {
 			continue
 		}
 
@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	tele "gopkg.in/telebot.v3"
+)
+
+// Broadcasts starts scheduled broadcasts whose time has come and keeps the
+// progress messages of the ones being delivered up to date.
+func (n *Notificator) Broadcasts(ctx context.Context) error {
+	started, err := n.broadcasts.StartDue(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range started {
+		log := slog.With(slog.Int64("broadcast_id", b.ID))
+		log.Info("notifications: scheduled broadcast started", slog.Int("total", b.Total))
+
+		content := n.broadcasts.FormatProgress(&b, domain.OutboxStats{Pending: uint(b.Total)})
+		msg, err := n.bot.Send(&tele.User{ID: b.AuthorID}, content)
+		if err != nil {
+			log.Error("notifications: send broadcast progress", sl.Err(err))
+			continue
+		}
+
+		if err := n.broadcasts.SetProgressMessage(ctx, b.ID, msg.ID); err != nil {
+			log.Error("notifications: store broadcast progress message", sl.Err(err))
+		}
+	}
+
+	list, err := n.broadcasts.Sending(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range list {
+		log := slog.With(slog.Int64("broadcast_id", b.ID))
+
+		stats, err := n.broadcasts.Progress(ctx, &b)
+		if err != nil {
+			log.Error("notifications: get broadcast progress", sl.Err(err))
+			continue
+		}
+
+		if b.ProgressMessageID == 0 {
+			continue
+		}
+
+		progress := &tele.Message{ID: b.ProgressMessageID, Chat: &tele.Chat{ID: b.AuthorID}}
+		_, err = n.bot.Edit(progress, n.broadcasts.FormatProgress(&b, stats))
+		if err != nil && !errors.Is(err, tele.ErrSameMessageContent) && !errors.Is(err, tele.ErrMessageNotModified) {
+			log.Error("notifications: edit broadcast progress", sl.Err(err))
+		}
+	}
+
+	return nil
+}
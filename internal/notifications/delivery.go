@@ -0,0 +1,29 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+)
+
+// deliver queues the messages made by build unless the notification is already
+// on its way or sent. The delivery is claimed in the transaction that queues
+// them, and if queueing or sending fails a later run tries again. Reports
+// whether the notification is queued now or was before.
+func (n *Notificator) deliver(ctx context.Context, kind domain.DeliveryKind, subject int64, recipient int64, build func() ([]domain.OutboxMessage, error)) bool {
+	log := slog.With(slog.String("kind", string(kind)), slog.Int64("subject", subject), slog.Int64("telegram_id", recipient))
+
+	msgs, err := build()
+	if err != nil {
+		log.Error("notifications: build delivery", sl.Err(err))
+		return false
+	}
+
+	if _, err := n.deliveries.Deliver(ctx, kind, subject, recipient, msgs); err != nil {
+		log.Error("notifications: deliver", sl.Err(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/invite"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+)
+
+// FeedbackRequest asks participants who confirmed or arrived to rate their meeting
+// once it is FeedbackAfter past its start. Each participant is asked once, and
+// not at all if they have muted feedback questions or can't get messages.
+func (n *Notificator) FeedbackRequest(ctx context.Context) error {
+	if n.quiet() {
+		return nil
+	}
+
+	list, err := n.meetings.GetMeetingsForFeedbackRequest(ctx, n.config.FeedbackAfter)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range list {
+		log := slog.With(slog.Int64("meeting_id", m.ID))
+
+		for _, id := range m.FeedbackRecipients() {
+			if !n.reachable(ctx, id) {
+				continue
+			}
+
+			partnerID := m.Partner(id)
+			partner, err := n.users.GetUser(ctx, partnerID)
+			if err != nil || partner == nil {
+				log.Error("notifications: get partner", sl.Err(err), slog.Int64("telegram_id", partnerID))
+				continue
+			}
+
+			muted, err := n.users.IsMuted(ctx, id, domain.NotificationFeedback)
+			if err != nil {
+				log.Error("notifications: check muted", sl.Err(err), slog.Int64("telegram_id", id))
+				continue
+			}
+
+			// a muted participant is recorded as asked with nothing queued,
+			// so they are not asked after unmuting either
+			n.deliver(ctx, domain.DeliveryFeedbackRequest, m.ID, id, func() ([]domain.OutboxMessage, error) {
+				if muted {
+					return nil, nil
+				}
+				msg, err := invite.RatingRequest(id, m.ID, partner)
+				return []domain.OutboxMessage{msg}, err
+			})
+		}
+	}
+
+	return nil
+}
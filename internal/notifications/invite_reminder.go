@@ -6,9 +6,9 @@ import (
 	"log/slog"
 
 	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
 	"github.com/jus1d/kypidbot/internal/domain"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
-	tele "gopkg.in/telebot.v3"
 )
 
 const (
@@ -19,7 +19,7 @@ const (
 func (n *Notificator) InviteReminder(ctx context.Context) error {
 	value, _ := n.settings.Get(ctx, "registration_closed")
 	closed := value == "true"
-	if closed {
+	if closed || n.quiet() {
 		return nil
 	}
 
@@ -48,13 +48,10 @@ func (n *Notificator) InviteReminder(ctx context.Context) error {
 		link := fmt.Sprintf("https://t.me/%s?start=%s", n.bot.Me.Username, code)
 		text := messages.Format(messages.M.Notifications.Invite, map[string]string{"link": link})
 
-		if _, err := n.bot.Send(&tele.User{ID: u.TelegramID}, text); err != nil {
-			log.Error("notifications: send invite reminder", sl.Err(err))
-		}
-
-		if err := n.users.MarkInviteNotified(ctx, u.TelegramID); err != nil {
-			log.Error("notifications: mark invite notified", sl.Err(err))
-		}
+		n.deliver(ctx, domain.DeliveryInviteReminder, u.TelegramID, u.TelegramID, func() ([]domain.OutboxMessage, error) {
+			m, err := outbox.TextMessage(u.TelegramID, text, nil)
+			return []domain.OutboxMessage{m}, err
+		})
 	}
 
 	return nil
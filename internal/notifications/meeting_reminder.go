@@ -1,19 +1,36 @@
 package notifications
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
+	"github.com/jus1d/kypidbot/internal/config"
 	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
 	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
 	"github.com/jus1d/kypidbot/internal/domain"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
 	tele "gopkg.in/telebot.v3"
 )
 
+// MeetingReminder sends the configured reminders to participants of upcoming meetings.
+// Only the latest due reminder is sent, so after downtime a participant gets one
+// reminder instead of all the missed ones. Participants who have not confirmed yet
+// are asked to do it.
 func (n *Notificator) MeetingReminder(ctx context.Context) error {
-	list, err := n.meetings.GetMeetingsStartingIn(ctx, n.config.DateUpcomingIn)
+	reminders := slices.Clone(n.config.MeetingReminders)
+	if len(reminders) == 0 {
+		return nil
+	}
+	slices.SortFunc(reminders, func(a, b config.Reminder) int {
+		return cmp.Compare(a.Before, b.Before)
+	})
+
+	list, err := n.meetings.GetMeetingsStartingIn(ctx, reminders[len(reminders)-1].Before)
 	if err != nil {
 		return err
 	}
@@ -21,50 +38,78 @@ func (n *Notificator) MeetingReminder(ctx context.Context) error {
 	for _, m := range list {
 		log := slog.With(slog.Int64("meeting_id", m.ID))
 
-		if m.UsersNotified {
+		if m.PlaceID == nil || m.Time == nil {
 			continue
 		}
 
-		if m.DillState != domain.StateConfirmed || m.DoeState != domain.StateConfirmed {
+		if m.DillState.Dropped() || m.DoeState.Dropped() {
 			continue
 		}
 
-		if m.PlaceID == nil || m.Time == nil {
+		until := time.Until(*m.Time)
+		i := slices.IndexFunc(reminders, func(r config.Reminder) bool {
+			return until <= r.Before
+		})
+		if i < 0 {
 			continue
 		}
 
-		dill, err := n.users.GetUser(ctx, m.DillID)
-		if err != nil {
-			log.Error("notifications: get dill", sl.Err(err))
+		place, err := n.places.GetPlace(ctx, *m.PlaceID)
+		if err != nil || place == nil {
+			log.Error("notifications: get place", sl.Err(err))
 			continue
 		}
 
-		doe, err := n.users.GetUser(ctx, m.DoeID)
-		if err != nil {
-			log.Error("notifications: get doe", sl.Err(err))
-			continue
+		participants := map[int64]domain.ConfirmationState{
+			m.DillID: m.DillState,
+			m.DoeID:  m.DoeState,
 		}
-
-		if dill == nil || doe == nil {
-			continue
+		for id, state := range participants {
+			if state != domain.StateConfirmed && state != domain.StateNotConfirmed {
+				continue
+			}
+			if !n.reachable(ctx, id) {
+				continue
+			}
+
+			n.deliver(ctx, domain.MeetingReminderDelivery(reminders[i].Before), m.ID, id, func() ([]domain.OutboxMessage, error) {
+				return reminder(id, &m, place, reminders[i], state == domain.StateConfirmed, i == 0)
+			})
 		}
+	}
 
-		msg := messages.M.Notifications.MeetingSoon
-
-		kb := view.ArrivedKeyboard(fmt.Sprintf("%d", m.ID))
-
-		if _, err := n.bot.Send(&tele.User{ID: dill.TelegramID}, msg, kb); err != nil {
-			log.Error("notifications: send to dill", sl.Err(err), slog.Int64("telegram_id", dill.TelegramID))
-		}
+	return nil
+}
 
-		if _, err := n.bot.Send(&tele.User{ID: doe.TelegramID}, msg, kb); err != nil {
-			log.Error("notifications: send to doe", sl.Err(err), slog.Int64("telegram_id", doe.TelegramID))
-		}
+// reminder returns the messages of a single reminder. The last one before the
+// meeting lets the participant report arrival and comes with the place's map pin.
+func reminder(telegramID int64, m *domain.Meeting, place *domain.Place, r config.Reminder, confirmed bool, last bool) ([]domain.OutboxMessage, error) {
+	msg := messages.Format(messages.M.Notifications.Reminders.Templates[r.Template], map[string]string{
+		"time":  domain.Timef(*m.Time),
+		"place": place.Description,
+	})
+
+	meetingID := fmt.Sprintf("%d", m.ID)
+
+	var kb *tele.ReplyMarkup
+	switch {
+	case !confirmed:
+		msg = fmt.Sprintf("%s\n\n%s", msg, messages.M.Notifications.Reminders.Unconfirmed)
+		kb = view.MeetingKeyboard(meetingID)
+	case last:
+		kb = view.ArrivedKeyboard(meetingID)
+	default:
+		kb = view.CancelKeyboard(meetingID)
+	}
 
-		if err := n.meetings.MarkNotified(ctx, m.ID); err != nil {
-			log.Error("notifications: mark notified", sl.Err(err))
-		}
+	text, err := outbox.TextMessage(telegramID, msg, kb)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	msgs := []domain.OutboxMessage{text}
+	if last && place.HasLocation() {
+		msgs = append(msgs, outbox.VenueMessage(telegramID, place))
+	}
+	return msgs, nil
 }
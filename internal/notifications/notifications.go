@@ -3,57 +3,166 @@ package notifications
 import (
 	"context"
 	"log/slog"
+	"math/rand/v2"
 	"time"
 
 	"github.com/jus1d/kypidbot/internal/config"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/invite"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
 	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/cron"
 	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+	"github.com/jus1d/kypidbot/internal/usecase"
 	tele "gopkg.in/telebot.v3"
 )
 
 type NotifyFunc func(ctx context.Context) error
 
 type Notificator struct {
-	bot      *tele.Bot
-	users    domain.UserRepository
-	places   domain.PlaceRepository
-	meetings domain.MeetingRepository
-	config   *config.Notifications
-	settings domain.SettingsRepository
-	funcs    []NotifyFunc
+	bot        *tele.Bot
+	invites    *invite.Sender
+	outbox     *outbox.Sender
+	users      domain.UserRepository
+	places     domain.PlaceRepository
+	meetings   domain.MeetingRepository
+	deliveries domain.DeliveryRepository
+	config     *config.Notifications
+	settings   domain.SettingsRepository
+	matching   *usecase.Matching
+	meeting    *usecase.Meeting
+	broadcasts *usecase.Broadcasts
+	jobs       domain.JobRepository
+	scheduled  []job
 }
 
-func New(c *config.Notifications, bot *tele.Bot, users domain.UserRepository, places domain.PlaceRepository, meetings domain.MeetingRepository, settings domain.SettingsRepository) *Notificator {
+func New(c *config.Notifications, bot *tele.Bot, invites *invite.Sender, outbox *outbox.Sender, users domain.UserRepository, places domain.PlaceRepository, meetings domain.MeetingRepository, deliveries domain.DeliveryRepository, settings domain.SettingsRepository, matching *usecase.Matching, meeting *usecase.Meeting, broadcasts *usecase.Broadcasts, jobs domain.JobRepository) *Notificator {
 	return &Notificator{
-		bot:      bot,
-		users:    users,
-		places:   places,
-		meetings: meetings,
-		config:   c,
-		settings: settings,
+		bot:        bot,
+		invites:    invites,
+		outbox:     outbox,
+		users:      users,
+		places:     places,
+		meetings:   meetings,
+		deliveries: deliveries,
+		config:     c,
+		settings:   settings,
+		matching:   matching,
+		meeting:    meeting,
+		broadcasts: broadcasts,
+		jobs:       jobs,
 	}
 }
 
-func (n *Notificator) Register(fns ...NotifyFunc) {
-	n.funcs = append(n.funcs, fns...)
+// Register adds the job under name, scheduled as configured in
+// notifications.jobs, or every PollInterval if it is not there.
+func (n *Notificator) Register(name string, fn NotifyFunc) {
+	j := job{name: name, fn: fn, schedule: every(n.config.PollInterval)}
+
+	if c, ok := n.config.Jobs[name]; ok {
+		j.jitter = c.Jitter
+		switch {
+		case c.Cron != "":
+			// the spec is validated when the config is loaded
+			s, _ := cron.Parse(c.Cron)
+			j.schedule = s
+		case c.Interval > 0:
+			j.schedule = every(c.Interval)
+		}
+	}
+
+	n.scheduled = append(n.scheduled, j)
 }
 
+// Run runs every registered job on its own schedule until ctx is done.
 func (n *Notificator) Run(ctx context.Context) {
+	for _, j := range n.scheduled {
+		go n.loop(ctx, j)
+	}
+	<-ctx.Done()
+}
+
+func (n *Notificator) loop(ctx context.Context, j job) {
+	loc, err := time.LoadLocation("Europe/Samara")
+	if err != nil {
+		slog.Error("notifications: load location", sl.Err(err))
+		return
+	}
+
 	for {
-		select {
-		case <-ctx.Done():
+		slot := j.schedule.Next(time.Now().In(loc))
+		if slot.IsZero() {
+			slog.Error("notifications: job never runs", slog.String("job", j.name), slog.String("schedule", j.schedule.String()))
 			return
-		default:
 		}
 
-		for _, fn := range n.funcs {
-			if err := fn(ctx); err != nil {
-				slog.Error("notifications: notify func failed", sl.Err(err))
-			}
+		sleep(ctx, time.Until(slot)+j.delay())
+		if ctx.Err() != nil {
+			return
 		}
 
-		sleep(ctx, n.config.PollInterval)
+		n.run(ctx, j, slot)
+	}
+}
+
+// run runs the job for the slot, unless another replica is running it or
+// has already run this slot.
+func (n *Notificator) run(ctx context.Context, j job, slot time.Time) {
+	log := slog.With(slog.String("job", j.name))
+
+	release, ok, err := n.jobs.TryLock(ctx, j.name)
+	if err != nil {
+		log.Error("notifications: lock job", sl.Err(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	defer release()
+
+	last, err := n.jobs.GetJob(ctx, j.name)
+	if err != nil {
+		log.Error("notifications: get job", sl.Err(err))
+		return
+	}
+	if last != nil && last.LastSlotAt != nil && !last.LastSlotAt.Before(slot) {
+		return
+	}
+
+	if err := n.jobs.StartRun(ctx, j.name, j.schedule.String(), slot); err != nil {
+		log.Error("notifications: start job run", sl.Err(err))
+		return
+	}
+
+	var lastError string
+	if err := j.fn(ctx); err != nil {
+		log.Error("notifications: job failed", sl.Err(err))
+		lastError = err.Error()
+	}
+
+	if err := n.jobs.FinishRun(ctx, j.name, lastError); err != nil {
+		log.Error("notifications: finish job run", sl.Err(err))
+	}
+}
+
+// quiet reports whether it is quiet hours in the event's timezone, when
+// non-urgent notifications wait. Messages about meetings are sent anyway.
+func (n *Notificator) quiet() bool {
+	loc, err := time.LoadLocation("Europe/Samara")
+	if err != nil {
+		return false
 	}
+	return n.config.QuietHours.Contains(time.Now().In(loc))
+}
+
+// reachable reports whether the user can get messages from the bot. Messages
+// to users flagged unreachable are not queued, since they would only fail.
+func (n *Notificator) reachable(ctx context.Context, telegramID int64) bool {
+	unreachable, err := n.users.IsUnreachable(ctx, telegramID)
+	if err != nil {
+		slog.Error("notifications: check unreachable", sl.Err(err), slog.Int64("telegram_id", telegramID))
+		return true
+	}
+	return !unreachable
 }
 
 func sleep(ctx context.Context, d time.Duration) {
@@ -62,3 +171,37 @@ func sleep(ctx context.Context, d time.Duration) {
 	case <-time.After(d):
 	}
 }
+
+type schedule interface {
+	Next(t time.Time) time.Time
+	String() string
+}
+
+type job struct {
+	name     string
+	fn       NotifyFunc
+	schedule schedule
+	jitter   time.Duration
+}
+
+// delay is the random delay of a run, so replicas and jobs scheduled at the
+// same time don't hit the database and Telegram at once.
+func (j job) delay() time.Duration {
+	if j.jitter <= 0 {
+		return 0
+	}
+	return rand.N(j.jitter)
+}
+
+// every runs a job at multiples of the interval, which are the same on every
+// replica.
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	d := time.Duration(e)
+	return t.Truncate(d).Add(d)
+}
+
+func (e every) String() string {
+	return "every " + time.Duration(e).String()
+}
@@ -2,17 +2,16 @@ package notifications
 
 import (
 	"context"
-	"log/slog"
 
 	"github.com/jus1d/kypidbot/internal/config/messages"
-	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
-	tele "gopkg.in/telebot.v3"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
+	"github.com/jus1d/kypidbot/internal/domain"
 )
 
 func (n *Notificator) RegisterReminder(ctx context.Context) error {
 	value, _ := n.settings.Get(ctx, "registration_closed")
 	closed := value == "true"
-	if closed {
+	if closed || n.quiet() {
 		return nil
 	}
 
@@ -22,20 +21,14 @@ func (n *Notificator) RegisterReminder(ctx context.Context) error {
 	}
 
 	for _, u := range list {
-		log := slog.With(slog.String("username", u.Username), slog.Int64("telegram_id", u.TelegramID))
-
 		if u.IsAdmin {
 			continue
 		}
 
-		content := messages.M.Notifications.Registration
-		if _, err := n.bot.Send(&tele.User{ID: u.TelegramID}, content); err != nil {
-			log.Error("notifications: send registration notification", sl.Err(err))
-		}
-
-		if err := n.users.MarkNotified(ctx, u.TelegramID); err != nil {
-			log.Error("notifications: mark user as notified", sl.Err(err))
-		}
+		n.deliver(ctx, domain.DeliveryRegistrationReminder, u.TelegramID, u.TelegramID, func() ([]domain.OutboxMessage, error) {
+			m, err := outbox.TextMessage(u.TelegramID, messages.M.Notifications.Registration, nil)
+			return []domain.OutboxMessage{m}, err
+		})
 	}
 
 	return nil
@@ -0,0 +1,91 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+)
+
+// ConfirmationDeadline expires invites that were not confirmed in time
+// and lets both participants know. The partner left alone is picked up by Rematch.
+func (n *Notificator) ConfirmationDeadline(ctx context.Context) error {
+	list, err := n.meeting.ExpireUnconfirmed(ctx, n.config.ConfirmationDeadline)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range list {
+		log := slog.With(slog.Int64("meeting_id", m.ID))
+
+		for _, p := range []struct {
+			id      int64
+			expired bool
+		}{
+			{m.DillID, m.DillState == domain.StateExpired},
+			{m.DoeID, m.DoeState == domain.StateExpired},
+		} {
+			msg := messages.M.Meeting.Status.Expired
+			if !p.expired {
+				msg = messages.M.Meeting.Status.PartnerExpired
+			}
+
+			if err := n.outbox.Text(ctx, p.id, msg, nil); err != nil {
+				log.Error("notifications: queue expired", sl.Err(err), slog.Int64("telegram_id", p.id))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Rematch pairs participants whose partner cancelled or expired with each other
+// and with unmatched users, and sends invites for the new meetings. Stranded
+// participants left without a pair are tried again on the next run, and told
+// only once no meeting can be scheduled for them anymore.
+func (n *Notificator) Rematch(ctx context.Context) error {
+	result, err := n.matching.Rematch(ctx)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+
+	// leave participants enough time to confirm the new invite
+	notBefore := time.Now().Add(n.config.ConfirmationDeadline)
+
+	schedule, err := n.meeting.ScheduleRematch(ctx, result, notBefore)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range schedule.Meetings {
+		n.invites.Send(ctx, m)
+
+		if err := n.meeting.MarkInvited(ctx, m.MeetingID); err != nil {
+			slog.Error("notifications: mark invited", sl.Err(err), slog.Int64("meeting_id", m.MeetingID))
+		}
+	}
+
+	for _, fm := range schedule.FullMatches {
+		n.invites.SendFullMatch(ctx, fm)
+	}
+
+	for _, id := range schedule.NotRematched {
+		if err := n.outbox.Text(ctx, id, messages.M.Matching.Success.NotRematched, nil); err != nil {
+			slog.Error("notifications: queue not rematched", sl.Err(err), slog.Int64("telegram_id", id))
+		}
+	}
+
+	slog.Info("notifications: rematched",
+		slog.Int("stranded", len(result.Stranded)),
+		slog.Int("meetings", len(schedule.Meetings)),
+		slog.Int("full_matches", len(schedule.FullMatches)),
+		slog.Int("not_rematched", len(schedule.NotRematched)))
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/outbox"
+	"github.com/jus1d/kypidbot/internal/delivery/telegram/view"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/logger/sl"
+)
+
+// SeeAgain asks both participants of a held meeting whether they want to meet each other again,
+// unless they have muted feedback questions or can't get messages.
+func (n *Notificator) SeeAgain(ctx context.Context) error {
+	if n.quiet() {
+		return nil
+	}
+
+	list, err := n.meeting.GetMeetingsForSeeAgain(ctx, n.config.SeeAgainAfter)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range list {
+		log := slog.With(slog.Int64("meeting_id", m.ID))
+
+		kb := view.SeeAgainKeyboard(fmt.Sprintf("%d", m.ID))
+
+		// the meeting is marked asked only once every question is queued; a
+		// question queued before is not queued again when this is retried
+		asked := true
+		for _, id := range []int64{m.DillID, m.DoeID} {
+			if !n.reachable(ctx, id) {
+				continue
+			}
+
+			muted, err := n.users.IsMuted(ctx, id, domain.NotificationFeedback)
+			if err != nil {
+				log.Error("notifications: check muted", sl.Err(err), slog.Int64("telegram_id", id))
+			}
+			if muted {
+				continue
+			}
+
+			asked = n.deliver(ctx, domain.DeliverySeeAgain, m.ID, id, func() ([]domain.OutboxMessage, error) {
+				msg, err := outbox.TextMessage(id, messages.M.Notifications.SeeAgain.Ask, kb)
+				return []domain.OutboxMessage{msg}, err
+			}) && asked
+		}
+		if !asked {
+			continue
+		}
+
+		if err := n.meeting.MarkSeeAgainAsked(ctx, m.ID); err != nil {
+			log.Error("notifications: mark see again asked", sl.Err(err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+type BlockRepo struct {
+	db *sql.DB
+}
+
+func NewBlockRepo(d *DB) *BlockRepo {
+	return &BlockRepo{db: d.db}
+}
+
+func (r *BlockRepo) Block(ctx context.Context, blockerID int64, blockedID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO blocks (blocker_id, blocked_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, blockerID, blockedID)
+	return err
+}
+
+func (r *BlockRepo) IsBlocked(ctx context.Context, a int64, b int64) (bool, error) {
+	var blocked bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)`, a, b).Scan(&blocked)
+	return blocked, err
+}
+
+func (r *BlockRepo) GetBlocks(ctx context.Context) ([]domain.Block, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT blocker_id, blocked_id FROM blocks`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []domain.Block
+	for rows.Next() {
+		var b domain.Block
+		if err := rows.Scan(&b.BlockerID, &b.BlockedID); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+const broadcastColumns = `id, author_id, status, segment, COALESCE(source_chat_id, 0), COALESCE(source_message_id, 0),
+	scheduled_at, total, COALESCE(progress_message_id, 0), created_at, started_at, finished_at`
+
+// segmentFilters select the recipients of a segment from users u.
+var segmentFilters = map[domain.BroadcastSegment]string{
+	domain.SegmentAll:          `TRUE`,
+	domain.SegmentRegistered:   `u.is_registered = TRUE AND u.opted_out = FALSE`,
+	domain.SegmentUnregistered: `u.is_registered = FALSE AND u.opted_out = FALSE`,
+	domain.SegmentOptedOut:     `u.opted_out = TRUE`,
+	domain.SegmentMatched: `EXISTS (
+		SELECT 1 FROM meetings m WHERE u.telegram_id IN (m.dill_id, m.doe_id))`,
+	domain.SegmentUnmatched: `u.is_registered = TRUE AND u.opted_out = FALSE AND NOT EXISTS (
+		SELECT 1 FROM meetings m WHERE u.telegram_id IN (m.dill_id, m.doe_id))`,
+	domain.SegmentConfirmed: `EXISTS (
+		SELECT 1 FROM meetings m
+		WHERE (m.dill_id = u.telegram_id AND m.dill_state IN ('confirmed', 'arrived'))
+		   OR (m.doe_id = u.telegram_id AND m.doe_state IN ('confirmed', 'arrived')))`,
+}
+
+type BroadcastRepo struct {
+	db *sql.DB
+}
+
+func NewBroadcastRepo(d *DB) *BroadcastRepo {
+	return &BroadcastRepo{db: d.db}
+}
+
+func (r *BroadcastRepo) CreateBroadcast(ctx context.Context, authorID int64) (*domain.Broadcast, error) {
+	return scanBroadcast(r.db.QueryRowContext(ctx, `
+		INSERT INTO broadcasts (author_id) VALUES ($1)
+		RETURNING `+broadcastColumns, authorID))
+}
+
+func (r *BroadcastRepo) GetBroadcast(ctx context.Context, id int64) (*domain.Broadcast, error) {
+	b, err := scanBroadcast(r.db.QueryRowContext(ctx, `
+		SELECT `+broadcastColumns+` FROM broadcasts WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return b, err
+}
+
+func (r *BroadcastRepo) GetDraft(ctx context.Context, authorID int64) (*domain.Broadcast, error) {
+	b, err := scanBroadcast(r.db.QueryRowContext(ctx, `
+		SELECT `+broadcastColumns+` FROM broadcasts
+		WHERE author_id = $1 AND status = 'draft'
+		ORDER BY id DESC LIMIT 1`, authorID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return b, err
+}
+
+func (r *BroadcastRepo) SetContent(ctx context.Context, id int64, chatID int64, messageID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE broadcasts SET source_chat_id = $1, source_message_id = $2
+		WHERE id = $3 AND status = 'draft'`, chatID, messageID, id)
+	return err
+}
+
+func (r *BroadcastRepo) SetSegment(ctx context.Context, id int64, segment domain.BroadcastSegment) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE broadcasts SET segment = $1 WHERE id = $2 AND status = 'draft'`, segment, id)
+	return err
+}
+
+func (r *BroadcastRepo) CountRecipients(ctx context.Context, segment domain.BroadcastSegment) (int, error) {
+	filter, err := recipientsFilter(segment)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users u WHERE `+filter).Scan(&count)
+	return count, err
+}
+
+func (r *BroadcastRepo) Schedule(ctx context.Context, id int64, at time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE broadcasts SET status = 'scheduled', scheduled_at = $1
+		WHERE id = $2 AND status = 'draft'`, at, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *BroadcastRepo) Cancel(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE broadcasts SET status = 'cancelled', finished_at = NOW()
+		WHERE id = $1 AND status IN ('draft', 'scheduled')`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *BroadcastRepo) Start(ctx context.Context, id int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var segment domain.BroadcastSegment
+	err = tx.QueryRowContext(ctx, `
+		UPDATE broadcasts SET status = 'sending', started_at = NOW()
+		WHERE id = $1 AND status IN ('draft', 'scheduled') AND source_message_id IS NOT NULL
+		RETURNING segment`, id).Scan(&segment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	filter, err := recipientsFilter(segment)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO outbox (chat_id, kind, from_chat_id, from_message_id, broadcast_id)
+		SELECT u.telegram_id, 'copy', b.source_chat_id, b.source_message_id, b.id
+		FROM users u, broadcasts b
+		WHERE b.id = $1 AND `+filter, id)
+	if err != nil {
+		return false, err
+	}
+
+	total, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE broadcasts SET total = $1 WHERE id = $2`, total, id); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *BroadcastRepo) GetDueScheduled(ctx context.Context) ([]domain.Broadcast, error) {
+	return r.queryBroadcasts(ctx, `
+		SELECT `+broadcastColumns+` FROM broadcasts
+		WHERE status = 'scheduled' AND scheduled_at <= NOW()
+		ORDER BY scheduled_at`)
+}
+
+func (r *BroadcastRepo) GetSending(ctx context.Context) ([]domain.Broadcast, error) {
+	return r.queryBroadcasts(ctx, `
+		SELECT `+broadcastColumns+` FROM broadcasts
+		WHERE status = 'sending'
+		ORDER BY id`)
+}
+
+func (r *BroadcastRepo) GetBroadcastProgress(ctx context.Context, id int64) (domain.OutboxStats, error) {
+	var s domain.OutboxStats
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+		COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+		COUNT(*) FILTER (WHERE status = 'sent') AS sent,
+		COUNT(*) FILTER (WHERE status = 'failed') AS failed
+		FROM outbox WHERE broadcast_id = $1`, id).Scan(&s.Pending, &s.Sent, &s.Failed)
+	if err != nil {
+		return domain.OutboxStats{}, err
+	}
+	return s, nil
+}
+
+func (r *BroadcastRepo) SetProgressMessage(ctx context.Context, id int64, messageID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE broadcasts SET progress_message_id = $1 WHERE id = $2`, messageID, id)
+	return err
+}
+
+func (r *BroadcastRepo) Finish(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE broadcasts SET status = 'done', finished_at = NOW()
+		WHERE id = $1 AND status = 'sending'`, id)
+	return err
+}
+
+func (r *BroadcastRepo) queryBroadcasts(ctx context.Context, query string, args ...any) ([]domain.Broadcast, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.Broadcast
+	for rows.Next() {
+		b, err := scanBroadcast(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *b)
+	}
+	return list, rows.Err()
+}
+
+func recipientsFilter(segment domain.BroadcastSegment) (string, error) {
+	filter, ok := segmentFilters[segment]
+	if !ok {
+		return "", fmt.Errorf("unknown broadcast segment: %q", segment)
+	}
+	return `u.banned = FALSE AND u.unreachable = FALSE AND ` + filter, nil
+}
+
+func scanBroadcast(row interface{ Scan(dest ...any) error }) (*domain.Broadcast, error) {
+	var b domain.Broadcast
+	if err := row.Scan(
+		&b.ID, &b.AuthorID, &b.Status, &b.Segment, &b.SourceChatID, &b.SourceMessageID,
+		&b.ScheduledAt, &b.Total, &b.ProgressMessageID, &b.CreatedAt, &b.StartedAt, &b.FinishedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type CheckInRepo struct {
+	db *sql.DB
+}
+
+func NewCheckInRepo(d *DB) *CheckInRepo {
+	return &CheckInRepo{db: d.db}
+}
+
+func (r *CheckInRepo) StartCheckIn(ctx context.Context, meetingID int64, telegramID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO check_ins (meeting_id, telegram_id) VALUES ($1, $2)
+		ON CONFLICT (meeting_id, telegram_id) DO UPDATE SET updated_at = NOW()`,
+		meetingID, telegramID)
+	return err
+}
+
+// GetPendingCheckIn returns the meeting the user most recently started an unverified check-in for, or 0.
+func (r *CheckInRepo) GetPendingCheckIn(ctx context.Context, telegramID int64) (int64, error) {
+	var meetingID int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT meeting_id FROM check_ins
+		WHERE telegram_id = $1 AND verified = FALSE
+		ORDER BY updated_at DESC
+		LIMIT 1`, telegramID).Scan(&meetingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return meetingID, err
+}
+
+func (r *CheckInRepo) SaveLocation(ctx context.Context, meetingID int64, telegramID int64, lat, lon, distance float64, verified bool) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE check_ins SET latitude = $1, longitude = $2, distance = $3, verified = $4, updated_at = NOW()
+		WHERE meeting_id = $5 AND telegram_id = $6`,
+		lat, lon, distance, verified, meetingID, telegramID)
+	return err
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+// maxDeliveryAttempts is how many times a failed delivery is claimed in total.
+const maxDeliveryAttempts = 3
+
+// delivered is the condition that the delivery d needs no further attempt: it
+// is claimed or sent, or it has failed too many times.
+func delivered(d string) string {
+	return fmt.Sprintf("(%[1]s.status <> 'failed' OR %[1]s.attempts >= %[2]d)", d, maxDeliveryAttempts)
+}
+
+type DeliveryRepo struct {
+	db *sql.DB
+}
+
+func NewDeliveryRepo(d *DB) *DeliveryRepo {
+	return &DeliveryRepo{db: d.db}
+}
+
+func (r *DeliveryRepo) Deliver(ctx context.Context, kind domain.DeliveryKind, subject int64, recipient int64, msgs []domain.OutboxMessage) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO deliveries AS d (kind, subject, recipient)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (kind, subject, recipient) DO UPDATE
+		SET status = 'claimed', attempts = d.attempts + 1, last_error = '', claimed_at = NOW(), sent_at = NULL
+		WHERE NOT `+delivered("d")+`
+		RETURNING id`,
+		string(kind), subject, recipient).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// messages of a failed attempt no longer settle the delivery
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE outbox SET delivery_id = NULL WHERE delivery_id = $1`, id); err != nil {
+		return false, err
+	}
+
+	for i := range msgs {
+		msgs[i].DeliveryID = &id
+		if err := enqueue(ctx, tx, &msgs[i]); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+type IncidentRepo struct {
+	db *sql.DB
+}
+
+func NewIncidentRepo(d *DB) *IncidentRepo {
+	return &IncidentRepo{db: d.db}
+}
+
+func (r *IncidentRepo) CreateIncident(ctx context.Context, i *domain.Incident) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO incidents (meeting_id, telegram_id)
+		VALUES ($1, $2)
+		RETURNING id, created_at`,
+		i.MeetingID, i.TelegramID,
+	).Scan(&i.ID, &i.CreatedAt)
+}
+
+func (r *IncidentRepo) GetIncident(ctx context.Context, id int64) (*domain.Incident, error) {
+	var i domain.Incident
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, meeting_id, telegram_id, created_at, acknowledged_by, acknowledged_at
+		FROM incidents WHERE id = $1`, id).Scan(
+		&i.ID, &i.MeetingID, &i.TelegramID, &i.CreatedAt, &i.AcknowledgedBy, &i.AcknowledgedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *IncidentRepo) AcknowledgeIncident(ctx context.Context, id int64, organizerID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE incidents SET acknowledged_by = $1, acknowledged_at = NOW()
+		WHERE id = $2 AND acknowledged_by IS NULL`, organizerID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+const jobColumns = `name, schedule, last_slot_at, last_run_at, last_finished_at, last_error`
+
+type JobRepo struct {
+	db *sql.DB
+}
+
+func NewJobRepo(d *DB) *JobRepo {
+	return &JobRepo{db: d.db}
+}
+
+// TryLock takes a session-level advisory lock on a connection of its own, so
+// the job does not keep a transaction open while it runs. The lock goes away
+// with the connection if it is lost.
+func (r *JobRepo) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ok bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext('job:' || $1))`, name).Scan(&ok)
+	if err != nil || !ok {
+		_ = conn.Close()
+		return nil, false, err
+	}
+
+	release := func() {
+		// the job's context may be done by now, e.g. on shutdown
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext('job:' || $1))`, name)
+		if err != nil {
+			// a connection that may still hold the lock must not go back to the pool
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}
+	return release, true, nil
+}
+
+func (r *JobRepo) GetJob(ctx context.Context, name string) (*domain.Job, error) {
+	j, err := scanJob(r.db.QueryRowContext(ctx, `
+		SELECT `+jobColumns+` FROM jobs WHERE name = $1`, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return j, err
+}
+
+func (r *JobRepo) GetJobs(ctx context.Context) ([]domain.Job, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+jobColumns+` FROM jobs ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []domain.Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *j)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *JobRepo) StartRun(ctx context.Context, name string, schedule string, slot time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO jobs (name, schedule, last_slot_at, last_run_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (name) DO UPDATE SET
+			schedule = EXCLUDED.schedule,
+			last_slot_at = EXCLUDED.last_slot_at,
+			last_run_at = EXCLUDED.last_run_at`,
+		name, schedule, slot)
+	return err
+}
+
+func (r *JobRepo) FinishRun(ctx context.Context, name string, lastError string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET last_finished_at = NOW(), last_error = $1 WHERE name = $2`, lastError, name)
+	return err
+}
+
+func scanJob(row interface{ Scan(dest ...any) error }) (*domain.Job, error) {
+	var j domain.Job
+	if err := row.Scan(&j.Name, &j.Schedule, &j.LastSlotAt, &j.LastRunAt, &j.LastFinishedAt, &j.LastError); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/jus1d/kypidbot/internal/domain"
 )
 
+const meetingColumns = `id, dill_id, doe_id, pair_score, is_fullmatch,
+		       place_id, time, dill_state, doe_state,
+		       dill_cant_find, doe_cant_find, invited_at, rematched,
+		       see_again_asked, dill_see_again, doe_see_again, revision`
+
 type MeetingRepo struct {
 	db *sql.DB
 }
@@ -28,17 +33,9 @@ func (r *MeetingRepo) SaveMeeting(ctx context.Context, m *domain.Meeting) error
 }
 
 func (r *MeetingRepo) GetMeetingByID(ctx context.Context, id int64) (*domain.Meeting, error) {
-	var m domain.Meeting
-
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, dill_id, doe_id, pair_score, is_fullmatch,
-		       place_id, time, dill_state, doe_state, users_notified,
-		       dill_cant_find, doe_cant_find
-		FROM meetings WHERE id = $1`, id).Scan(
-		&m.ID, &m.DillID, &m.DoeID, &m.PairScore, &m.IsFullmatch,
-		&m.PlaceID, &m.Time, &m.DillState, &m.DoeState, &m.UsersNotified,
-		&m.DillCantFind, &m.DoeCantFind,
-	)
+	m, err := scanMeeting(r.db.QueryRowContext(ctx, `
+		SELECT `+meetingColumns+`
+		FROM meetings WHERE id = $1`, id))
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -46,7 +43,7 @@ func (r *MeetingRepo) GetMeetingByID(ctx context.Context, id int64) (*domain.Mee
 		return nil, err
 	}
 
-	return &m, nil
+	return m, nil
 }
 
 func (r *MeetingRepo) GetRegularMeetings(ctx context.Context) ([]domain.Meeting, error) {
@@ -58,29 +55,9 @@ func (r *MeetingRepo) GetFullMeetings(ctx context.Context) ([]domain.Meeting, er
 }
 
 func (r *MeetingRepo) getMeetingsByFullmatch(ctx context.Context, fullmatch bool) ([]domain.Meeting, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, dill_id, doe_id, pair_score, is_fullmatch,
-		       place_id, time, dill_state, doe_state, users_notified,
-		       dill_cant_find, doe_cant_find
+	return r.queryMeetings(ctx, `
+		SELECT `+meetingColumns+`
 		FROM meetings WHERE is_fullmatch = $1`, fullmatch)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var meetings []domain.Meeting
-	for rows.Next() {
-		var m domain.Meeting
-		if err := rows.Scan(
-			&m.ID, &m.DillID, &m.DoeID, &m.PairScore, &m.IsFullmatch,
-			&m.PlaceID, &m.Time, &m.DillState, &m.DoeState, &m.UsersNotified,
-			&m.DillCantFind, &m.DoeCantFind,
-		); err != nil {
-			return nil, err
-		}
-		meetings = append(meetings, m)
-	}
-	return meetings, rows.Err()
 }
 
 func (r *MeetingRepo) AssignPlaceAndTime(ctx context.Context, id int64, placeID int64, time time.Time) error {
@@ -90,54 +67,61 @@ func (r *MeetingRepo) AssignPlaceAndTime(ctx context.Context, id int64, placeID
 	return err
 }
 
-func (r *MeetingRepo) UpdateState(ctx context.Context, meetingID int64, isDill bool, state domain.ConfirmationState) error {
-	col := "doe_state"
+// TransitionState moves the participant's state from one value to another and returns
+// the updated meeting, or nil if the participant is no longer in the from state.
+// Concurrent transitions of the same participant are serialized by the row lock,
+// so only one of them succeeds.
+func (r *MeetingRepo) TransitionState(ctx context.Context, meetingID int64, isDill bool, from, to domain.ConfirmationState) (*domain.Meeting, error) {
+	prefix := "doe"
 	if isDill {
-		col = "dill_state"
+		prefix = "dill"
 	}
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE meetings SET `+col+` = $1 WHERE id = $2`, state, meetingID)
-	return err
-}
 
-func (r *MeetingRepo) ClearMeetings(ctx context.Context) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM meetings`)
-	return err
-}
+	set := prefix + "_state = $1"
+	if to == domain.StateCancelled {
+		set += ", " + prefix + "_cancelled_at = NOW()"
+	}
 
-func (r *MeetingRepo) GetMeetingsStartingIn(ctx context.Context, interval time.Duration) ([]domain.Meeting, error) {
-	secs := fmt.Sprintf("%ds", int(interval.Seconds()))
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, dill_id, doe_id, pair_score, is_fullmatch,
-		       place_id, time, dill_state, doe_state, users_notified,
-		       dill_cant_find, doe_cant_find
-		FROM meetings WHERE time >= NOW() AND time <= NOW() + $1::interval AND users_notified = FALSE`, secs)
+	m, err := scanMeeting(r.db.QueryRowContext(ctx, `
+		UPDATE meetings SET `+set+`
+		WHERE id = $2 AND `+prefix+`_state = $3
+		RETURNING `+meetingColumns, to, meetingID, from))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var meetings []domain.Meeting
-	for rows.Next() {
-		var m domain.Meeting
-		if err := rows.Scan(
-			&m.ID, &m.DillID, &m.DoeID, &m.PairScore, &m.IsFullmatch,
-			&m.PlaceID, &m.Time, &m.DillState, &m.DoeState, &m.UsersNotified,
-			&m.DillCantFind, &m.DoeCantFind,
-		); err != nil {
-			return nil, err
-		}
-		meetings = append(meetings, m)
-	}
-	return meetings, rows.Err()
+	return m, nil
 }
 
-func (r *MeetingRepo) MarkNotified(ctx context.Context, meetingID int64) error {
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE meetings SET users_notified = TRUE WHERE id = $1`, meetingID)
+// ClearMeetings moves all meetings to the archive, keeping only what is
+// needed to compute participants' reliability and match success.
+func (r *MeetingRepo) ClearMeetings(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH archived AS (
+			DELETE FROM meetings
+			RETURNING id, dill_id, doe_id, time, dill_state, doe_state,
+			          dill_cant_find, doe_cant_find, dill_cancelled_at, doe_cancelled_at,
+			          see_again_asked, dill_see_again, doe_see_again
+		)
+		INSERT INTO meetings_archive (id, dill_id, doe_id, time, dill_state, doe_state,
+		                              dill_cant_find, doe_cant_find, dill_cancelled_at, doe_cancelled_at,
+		                              see_again_asked, dill_see_again, doe_see_again)
+		SELECT id, dill_id, doe_id, time, dill_state, doe_state,
+		       dill_cant_find, doe_cant_find, dill_cancelled_at, doe_cancelled_at,
+		       see_again_asked, dill_see_again, doe_see_again
+		FROM archived`)
 	return err
 }
 
+func (r *MeetingRepo) GetMeetingsStartingIn(ctx context.Context, interval time.Duration) ([]domain.Meeting, error) {
+	secs := fmt.Sprintf("%ds", int(interval.Seconds()))
+	return r.queryMeetings(ctx, `
+		SELECT `+meetingColumns+`
+		FROM meetings WHERE time >= NOW() AND time <= NOW() + $1::interval`, secs)
+}
+
 func (r *MeetingRepo) SetCantFind(ctx context.Context, meetingID int64, isDill bool) error {
 	col := "doe_cant_find"
 	if isDill {
@@ -168,40 +152,192 @@ func (r *MeetingRepo) GetMeetingStats(ctx context.Context) (domain.MeetingStats,
 	err := r.db.QueryRowContext(ctx, `SELECT
 		COUNT(*) AS total,
 		COUNT(*) FILTER (WHERE dill_state = 'confirmed' AND doe_state = 'confirmed') AS confirmed,
-		COUNT(*) FILTER (WHERE dill_state = 'cancelled' OR doe_state = 'cancelled') AS cancelled,
-		COUNT(*) FILTER (WHERE dill_state != 'cancelled' AND doe_state != 'cancelled'
-			AND NOT (dill_state = 'confirmed' AND doe_state = 'confirmed')) AS pending
-		FROM meetings`).Scan(&s.Total, &s.Confirmed, &s.Cancelled, &s.Pending)
+		COUNT(*) FILTER (WHERE dill_state IN ('cancelled', 'expired') OR doe_state IN ('cancelled', 'expired')) AS cancelled,
+		COUNT(*) FILTER (WHERE dill_state NOT IN ('cancelled', 'expired') AND doe_state NOT IN ('cancelled', 'expired')
+			AND NOT (dill_state = 'confirmed' AND doe_state = 'confirmed')) AS pending,
+		(SELECT COUNT(*) FROM (
+			SELECT dill_see_again, doe_see_again FROM meetings
+			UNION ALL
+			SELECT dill_see_again, doe_see_again FROM meetings_archive
+		) a WHERE dill_see_again IS NOT NULL AND doe_see_again IS NOT NULL) AS answered,
+		(SELECT COUNT(*) FROM (
+			SELECT dill_see_again, doe_see_again FROM meetings
+			UNION ALL
+			SELECT dill_see_again, doe_see_again FROM meetings_archive
+		) a WHERE dill_see_again AND doe_see_again) AS mutual
+		FROM meetings`).Scan(&s.Total, &s.Confirmed, &s.Cancelled, &s.Pending, &s.Answered, &s.Mutual)
 	if err != nil {
 		return domain.MeetingStats{}, err
 	}
 	return s, nil
 }
 
-func (r *MeetingRepo) GetTelegramIDsForFeedbackRequest(ctx context.Context) ([]int64, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT dill_id, doe_id FROM meetings
-		WHERE dill_state IN ('confirmed', 'arrived') AND doe_state IN ('confirmed', 'arrived')`)
+// GetMeetingsForFeedbackRequest returns meetings that started at least the given
+// time ago and have a participant who confirmed or arrived but was not asked for feedback yet.
+func (r *MeetingRepo) GetMeetingsForFeedbackRequest(ctx context.Context, after time.Duration) ([]domain.Meeting, error) {
+	secs := fmt.Sprintf("%ds", int(after.Seconds()))
+	return r.queryMeetings(ctx, `
+		SELECT `+meetingColumns+`
+		FROM meetings
+		WHERE time <= NOW() - $1::interval
+		  AND ((dill_state IN ('confirmed', 'arrived') AND NOT `+feedbackRequested("dill_id")+`)
+		    OR (doe_state IN ('confirmed', 'arrived') AND NOT `+feedbackRequested("doe_id")+`))`, secs)
+}
+
+// feedbackRequested is the condition that the participant in the given column
+// was asked to rate the meeting, or is not to be asked again.
+func feedbackRequested(col string) string {
+	return `EXISTS (SELECT 1 FROM deliveries d
+		WHERE d.kind = '` + string(domain.DeliveryFeedbackRequest) + `' AND d.subject = meetings.id AND d.recipient = meetings.` + col + `
+		  AND ` + delivered("d") + `)`
+}
+
+func (r *MeetingRepo) MarkInvited(ctx context.Context, meetingID int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE meetings SET invited_at = NOW() WHERE id = $1 AND invited_at IS NULL`, meetingID)
+	return err
+}
+
+// ExpireUnconfirmed marks participants who have not confirmed their invite
+// within the deadline as expired and returns the affected meetings.
+func (r *MeetingRepo) ExpireUnconfirmed(ctx context.Context, deadline time.Duration) ([]domain.Meeting, error) {
+	secs := fmt.Sprintf("%ds", int(deadline.Seconds()))
+	return r.queryMeetings(ctx, `
+		UPDATE meetings SET
+			dill_state = CASE WHEN dill_state = 'not_confirmed' THEN 'expired'::confirmation_state ELSE dill_state END,
+			doe_state = CASE WHEN doe_state = 'not_confirmed' THEN 'expired'::confirmation_state ELSE doe_state END
+		WHERE is_fullmatch = FALSE
+		  AND invited_at IS NOT NULL AND invited_at <= NOW() - $1::interval
+		  AND (dill_state = 'not_confirmed' OR doe_state = 'not_confirmed')
+		  AND dill_state != 'cancelled' AND doe_state != 'cancelled'
+		RETURNING `+meetingColumns, secs)
+}
+
+// GetStrandedMeetings returns meetings that one or both participants dropped
+// out of and that have not been through re-matching yet.
+func (r *MeetingRepo) GetStrandedMeetings(ctx context.Context) ([]domain.Meeting, error) {
+	return r.queryMeetings(ctx, `
+		SELECT `+meetingColumns+`
+		FROM meetings
+		WHERE is_fullmatch = FALSE AND rematched = FALSE
+		  AND (dill_state IN ('cancelled', 'expired') OR doe_state IN ('cancelled', 'expired'))`)
+}
+
+func (r *MeetingRepo) SaveRematch(ctx context.Context, meetings []domain.Meeting, rematched []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range meetings {
+		m := &meetings[i]
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO meetings (dill_id, doe_id, pair_score, is_fullmatch, place_id, time)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			m.DillID, m.DoeID, m.PairScore, m.IsFullmatch, m.PlaceID, m.Time,
+		).Scan(&m.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range rematched {
+		if _, err := tx.ExecContext(ctx, `UPDATE meetings SET rematched = TRUE WHERE id = $1`, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *MeetingRepo) DeleteMeeting(ctx context.Context, meetingID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM meetings WHERE id = $1`, meetingID)
+	return err
+}
+
+// Reschedule moves the meeting to a new place and time. Both participants
+// have to confirm it again, so the confirmation deadline restarts, and
+// reminders are sent anew for the new time.
+func (r *MeetingRepo) Reschedule(ctx context.Context, meetingID int64, placeID int64, time time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH reminders AS (
+			DELETE FROM deliveries WHERE kind LIKE 'meeting_reminder:%' AND subject = $3
+		)
+		UPDATE meetings SET place_id = $1, time = $2,
+			dill_state = 'not_confirmed', doe_state = 'not_confirmed',
+			invited_at = NOW(), revision = revision + 1
+		WHERE id = $3`, placeID, time, meetingID)
+	return err
+}
+
+// GetMeetingsForSeeAgain returns held meetings that ended at least the given time ago
+// and whose participants have not been asked whether they want to meet again.
+func (r *MeetingRepo) GetMeetingsForSeeAgain(ctx context.Context, after time.Duration) ([]domain.Meeting, error) {
+	secs := fmt.Sprintf("%ds", int(after.Seconds()))
+	return r.queryMeetings(ctx, `
+		SELECT `+meetingColumns+`
+		FROM meetings
+		WHERE see_again_asked = FALSE AND time <= NOW() - $1::interval
+		  AND dill_state IN ('confirmed', 'arrived') AND doe_state IN ('confirmed', 'arrived')`, secs)
+}
+
+func (r *MeetingRepo) MarkSeeAgainAsked(ctx context.Context, meetingID int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE meetings SET see_again_asked = TRUE WHERE id = $1`, meetingID)
+	return err
+}
+
+// SetSeeAgain returns the partner's answer from the same statement, so of two
+// participants answering yes at once exactly one completes the mutual wish:
+// the second update waits for the first and sees its answer.
+func (r *MeetingRepo) SetSeeAgain(ctx context.Context, meetingID int64, isDill bool, answer bool) (bool, bool, error) {
+	col, partnerCol := "doe_see_again", "dill_see_again"
+	if isDill {
+		col, partnerCol = "dill_see_again", "doe_see_again"
+	}
+
+	var partner sql.NullBool
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE meetings SET `+col+` = $1 WHERE id = $2 AND `+col+` IS NULL
+		RETURNING `+partnerCol, answer, meetingID).Scan(&partner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	return true, answer && partner.Valid && partner.Bool, nil
+}
+
+func (r *MeetingRepo) queryMeetings(ctx context.Context, query string, args ...any) ([]domain.Meeting, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	seen := make(map[int64]struct{})
-	var ids []int64
+	var meetings []domain.Meeting
 	for rows.Next() {
-		var dillID, doeID int64
-		if err := rows.Scan(&dillID, &doeID); err != nil {
+		m, err := scanMeeting(rows)
+		if err != nil {
 			return nil, err
 		}
-		if _, ok := seen[dillID]; !ok {
-			seen[dillID] = struct{}{}
-			ids = append(ids, dillID)
-		}
-		if _, ok := seen[doeID]; !ok {
-			seen[doeID] = struct{}{}
-			ids = append(ids, doeID)
-		}
+		meetings = append(meetings, *m)
+	}
+	return meetings, rows.Err()
+}
+
+func scanMeeting(row interface{ Scan(dest ...any) error }) (*domain.Meeting, error) {
+	var m domain.Meeting
+	if err := row.Scan(
+		&m.ID, &m.DillID, &m.DoeID, &m.PairScore, &m.IsFullmatch,
+		&m.PlaceID, &m.Time, &m.DillState, &m.DoeState,
+		&m.DillCantFind, &m.DoeCantFind, &m.InvitedAt, &m.Rematched,
+		&m.SeeAgainAsked, &m.DillSeeAgain, &m.DoeSeeAgain, &m.Revision,
+	); err != nil {
+		return nil, err
 	}
-	return ids, rows.Err()
+	return &m, nil
 }
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+type OutboxRepo struct {
+	db *sql.DB
+}
+
+func NewOutboxRepo(d *DB) *OutboxRepo {
+	return &OutboxRepo{db: d.db}
+}
+
+func (r *OutboxRepo) Enqueue(ctx context.Context, m *domain.OutboxMessage) error {
+	return enqueue(ctx, r.db, m)
+}
+
+// enqueue inserts the message with q, which is either the database or a
+// transaction the message is queued in.
+func enqueue(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}, m *domain.OutboxMessage) error {
+	var markup any
+	if len(m.Markup) > 0 {
+		markup = string(m.Markup)
+	}
+	var key any
+	if m.MessageKey != "" {
+		key = m.MessageKey
+	}
+
+	return q.QueryRowContext(ctx, `
+		INSERT INTO outbox (chat_id, kind, text, markup, place_id, meeting_id, message_key, delivery_id)
+		VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7, $8)
+		RETURNING id, status, next_attempt_at, created_at`,
+		m.ChatID, m.Kind, m.Text, markup, m.PlaceID, m.MeetingID, key, m.DeliveryID,
+	).Scan(&m.ID, &m.Status, &m.NextAttemptAt, &m.CreatedAt)
+}
+
+func (r *OutboxRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	secs := fmt.Sprintf("%ds", int(lease.Seconds()))
+	rows, err := r.db.QueryContext(ctx, `
+		WITH claimed AS (
+			UPDATE outbox SET next_attempt_at = NOW() + $2::interval
+			WHERE id IN (
+				SELECT id FROM outbox
+				WHERE status = 'pending' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, chat_id, kind, text, markup, place_id, meeting_id, message_key,
+			          from_chat_id, from_message_id, broadcast_id, delivery_id, status, attempts, next_attempt_at, created_at
+		)
+		SELECT id, chat_id, kind, text, COALESCE(markup::text, ''), place_id, meeting_id, COALESCE(message_key, ''),
+		       from_chat_id, from_message_id, broadcast_id, delivery_id, status, attempts, next_attempt_at, created_at
+		FROM claimed
+		ORDER BY id`,
+		limit, secs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.OutboxMessage
+	for rows.Next() {
+		var (
+			m      domain.OutboxMessage
+			markup string
+		)
+		if err := rows.Scan(
+			&m.ID, &m.ChatID, &m.Kind, &m.Text, &markup, &m.PlaceID, &m.MeetingID, &m.MessageKey,
+			&m.FromChatID, &m.FromMessageID, &m.BroadcastID, &m.DeliveryID, &m.Status, &m.Attempts, &m.NextAttemptAt, &m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if markup != "" {
+			m.Markup = []byte(markup)
+		}
+		list = append(list, m)
+	}
+
+	return list, rows.Err()
+}
+
+func (r *OutboxRepo) MarkSent(ctx context.Context, id int64, messageID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE outbox SET status = 'sent', message_id = $1, sent_at = NOW(), last_error = NULL
+		WHERE id = $2`, messageID, id)
+	if err != nil {
+		return err
+	}
+
+	// run after the message is committed as sent, so that of two messages of
+	// the delivery sent at once, the later one sees the other and settles it
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE deliveries d SET status = 'sent', sent_at = NOW()
+		FROM outbox o
+		WHERE o.id = $1 AND d.id = o.delivery_id AND d.status = 'claimed'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM outbox p
+		      WHERE p.delivery_id = d.id AND p.status <> 'sent')`, id)
+	return err
+}
+
+func (r *OutboxRepo) Retry(ctx context.Context, id int64, at time.Time, failed bool, lastError string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE outbox
+		SET next_attempt_at = $1,
+		    attempts = attempts + CASE WHEN $2 THEN 1 ELSE 0 END,
+		    last_error = COALESCE(NULLIF($3, ''), last_error)
+		WHERE id = $4`, at, failed, lastError, id)
+	return err
+}
+
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH failed AS (
+			UPDATE outbox SET status = 'failed', attempts = attempts + 1, last_error = $1
+			WHERE id = $2
+			RETURNING delivery_id
+		)
+		UPDATE deliveries d SET status = 'failed', last_error = $1
+		FROM failed
+		WHERE d.id = failed.delivery_id AND d.status = 'claimed'`, lastError, id)
+	return err
+}
+
+func (r *OutboxRepo) GetOutboxStats(ctx context.Context) (domain.OutboxStats, error) {
+	var s domain.OutboxStats
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+		COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+		COUNT(*) FILTER (WHERE status = 'sent') AS sent,
+		COUNT(*) FILTER (WHERE status = 'failed') AS failed
+		FROM outbox`).Scan(&s.Pending, &s.Sent, &s.Failed)
+	if err != nil {
+		return domain.OutboxStats{}, err
+	}
+	return s, nil
+}
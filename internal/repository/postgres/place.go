@@ -3,10 +3,13 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/jus1d/kypidbot/internal/domain"
 )
 
+const placeColumns = `id, description, photo_url, photo_file_id, route, quality, is_active, latitude, longitude, radius`
+
 type PlaceRepo struct {
 	db *sql.DB
 }
@@ -15,25 +18,59 @@ func NewPlaceRepo(d *DB) *PlaceRepo {
 	return &PlaceRepo{db: d.db}
 }
 
-func (r *PlaceRepo) SavePlace(ctx context.Context, description string) error {
+func (r *PlaceRepo) SavePlace(ctx context.Context, p *domain.Place) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO places (description, photo_url, photo_file_id, route, quality, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, radius`,
+		p.Description, p.PhotoURL, p.PhotoFileID, p.Route, p.Quality, p.IsActive,
+	).Scan(&p.ID, &p.Radius)
+}
+
+func (r *PlaceRepo) UpdatePlace(ctx context.Context, p *domain.Place) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE places SET description = $1, photo_url = $2, photo_file_id = $3, route = $4, quality = $5,
+			latitude = $6, longitude = $7, radius = $8
+		WHERE id = $9`,
+		p.Description, p.PhotoURL, p.PhotoFileID, p.Route, p.Quality,
+		p.Latitude, p.Longitude, p.Radius, p.ID)
+	return err
+}
+
+func (r *PlaceRepo) SetPlaceActive(ctx context.Context, placeID int64, active bool) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE places SET is_active = $1 WHERE id = $2`, active, placeID)
+	return err
+}
+
+func (r *PlaceRepo) SetPhotoFileID(ctx context.Context, placeID int64, fileID string) error {
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO places (description) VALUES ($1)`, description)
+		`UPDATE places SET photo_file_id = $1 WHERE id = $2`, fileID, placeID)
 	return err
 }
 
 func (r *PlaceRepo) GetPlace(ctx context.Context, placeID int64) (*domain.Place, error) {
-	var p domain.Place
-	err := r.db.QueryRowContext(ctx,
-		`SELECT id, description, photo_url, route, quality FROM places WHERE id = $1`,
-		placeID).Scan(&p.ID, &p.Description, &p.PhotoURL, &p.Route, &p.Quality)
+	p, err := scanPlace(r.db.QueryRowContext(ctx,
+		`SELECT `+placeColumns+` FROM places WHERE id = $1`, placeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	return &p, nil
+	return p, nil
 }
 
 func (r *PlaceRepo) GetAllPlaces(ctx context.Context) ([]domain.Place, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, description, photo_url, route, quality FROM places ORDER BY quality DESC`)
+	return r.getPlaces(ctx, `SELECT `+placeColumns+` FROM places ORDER BY quality DESC, id`)
+}
+
+func (r *PlaceRepo) GetActivePlaces(ctx context.Context) ([]domain.Place, error) {
+	return r.getPlaces(ctx, `SELECT `+placeColumns+` FROM places WHERE is_active = TRUE ORDER BY quality DESC, id`)
+}
+
+func (r *PlaceRepo) getPlaces(ctx context.Context, query string) ([]domain.Place, error) {
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +78,48 @@ func (r *PlaceRepo) GetAllPlaces(ctx context.Context) ([]domain.Place, error) {
 
 	var places []domain.Place
 	for rows.Next() {
-		var p domain.Place
-		if err := rows.Scan(&p.ID, &p.Description, &p.PhotoURL, &p.Route, &p.Quality); err != nil {
+		p, err := scanPlace(rows)
+		if err != nil {
 			return nil, err
 		}
-		places = append(places, p)
+		places = append(places, *p)
 	}
 	return places, rows.Err()
 }
+
+func scanPlace(row interface{ Scan(dest ...any) error }) (*domain.Place, error) {
+	var p domain.Place
+	if err := row.Scan(
+		&p.ID, &p.Description, &p.PhotoURL, &p.PhotoFileID, &p.Route, &p.Quality, &p.IsActive,
+		&p.Latitude, &p.Longitude, &p.Radius,
+	); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PlaceRepo) SetPendingEdit(ctx context.Context, telegramID int64, e domain.PlaceEdit) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO place_edits (telegram_id, place_id, field) VALUES ($1, $2, $3)
+		ON CONFLICT (telegram_id) DO UPDATE SET place_id = EXCLUDED.place_id, field = EXCLUDED.field`,
+		telegramID, e.PlaceID, e.Field)
+	return err
+}
+
+func (r *PlaceRepo) GetPendingEdit(ctx context.Context, telegramID int64) (*domain.PlaceEdit, error) {
+	var e domain.PlaceEdit
+	err := r.db.QueryRowContext(ctx,
+		`SELECT place_id, field FROM place_edits WHERE telegram_id = $1`, telegramID).Scan(&e.PlaceID, &e.Field)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *PlaceRepo) ClearPendingEdit(ctx context.Context, telegramID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM place_edits WHERE telegram_id = $1`, telegramID)
+	return err
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+type ProposalRepo struct {
+	db *sql.DB
+}
+
+func NewProposalRepo(d *DB) *ProposalRepo {
+	return &ProposalRepo{db: d.db}
+}
+
+func (r *ProposalRepo) SaveProposal(ctx context.Context, p *domain.Proposal) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO meeting_proposals (meeting_id, proposer_id, time)
+		VALUES ($1, $2, $3)
+		RETURNING id, status`,
+		p.MeetingID, p.ProposerID, p.Time,
+	).Scan(&p.ID, &p.Status)
+}
+
+func (r *ProposalRepo) GetProposal(ctx context.Context, id int64) (*domain.Proposal, error) {
+	var p domain.Proposal
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, meeting_id, proposer_id, time, status
+		FROM meeting_proposals WHERE id = $1`, id).Scan(
+		&p.ID, &p.MeetingID, &p.ProposerID, &p.Time, &p.Status,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ResolveProposal moves a pending proposal to the given status.
+// Reports false if the proposal has already been resolved.
+func (r *ProposalRepo) ResolveProposal(ctx context.Context, id int64, status domain.ProposalStatus) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE meeting_proposals SET status = $1
+		WHERE id = $2 AND status = 'pending'`, status, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *ProposalRepo) DeclinePendingProposals(ctx context.Context, meetingID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE meeting_proposals SET status = 'declined'
+		WHERE meeting_id = $1 AND status = 'pending'`, meetingID)
+	return err
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+type RatingRepo struct {
+	db *sql.DB
+}
+
+func NewRatingRepo(d *DB) *RatingRepo {
+	return &RatingRepo{db: d.db}
+}
+
+func (r *RatingRepo) SaveStars(ctx context.Context, meetingID int64, raterID int64, stars int) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO meeting_ratings (meeting_id, rater_id, stars)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (meeting_id, rater_id) DO UPDATE SET stars = EXCLUDED.stars, updated_at = NOW()`,
+		meetingID, raterID, stars)
+	return err
+}
+
+func (r *RatingRepo) GetRating(ctx context.Context, meetingID int64, raterID int64) (*domain.Rating, error) {
+	var (
+		rating  domain.Rating
+		tags    string
+		comment sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT meeting_id, rater_id, stars, array_to_string(tags, ','), comment, created_at
+		FROM meeting_ratings WHERE meeting_id = $1 AND rater_id = $2`,
+		meetingID, raterID).Scan(&rating.MeetingID, &rating.RaterID, &rating.Stars, &tags, &comment, &rating.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tags != "" {
+		for _, t := range strings.Split(tags, ",") {
+			rating.Tags = append(rating.Tags, domain.RatingTag(t))
+		}
+	}
+	rating.Comment = comment.String
+	return &rating, nil
+}
+
+func (r *RatingRepo) SetTags(ctx context.Context, meetingID int64, raterID int64, tags []domain.RatingTag) error {
+	list := make([]string, len(tags))
+	for i, t := range tags {
+		list[i] = string(t)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE meeting_ratings SET tags = string_to_array($1, ','), updated_at = NOW()
+		WHERE meeting_id = $2 AND rater_id = $3`,
+		strings.Join(list, ","), meetingID, raterID)
+	return err
+}
+
+func (r *RatingRepo) SetLatestComment(ctx context.Context, raterID int64, comment string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE meeting_ratings SET comment = $1, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM meeting_ratings WHERE rater_id = $2
+			ORDER BY updated_at DESC LIMIT 1
+		)`, comment, raterID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *RatingRepo) GetRatingStats(ctx context.Context) (domain.RatingStats, error) {
+	var (
+		s       domain.RatingStats
+		average sql.NullFloat64
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), AVG(stars) FROM meeting_ratings`).Scan(&s.Count, &average)
+	if err != nil {
+		return domain.RatingStats{}, err
+	}
+	s.Average = average.Float64
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tag, COUNT(*) FROM meeting_ratings, unnest(tags) AS tag
+		GROUP BY tag`)
+	if err != nil {
+		return domain.RatingStats{}, err
+	}
+	defer rows.Close()
+
+	s.Tags = make(map[domain.RatingTag]uint)
+	for rows.Next() {
+		var (
+			tag   string
+			count uint
+		)
+		if err := rows.Scan(&tag, &count); err != nil {
+			return domain.RatingStats{}, err
+		}
+		s.Tags[domain.RatingTag(tag)] = count
+	}
+	return s, rows.Err()
+}
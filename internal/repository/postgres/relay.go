@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+type RelayRepo struct {
+	db *sql.DB
+}
+
+func NewRelayRepo(d *DB) *RelayRepo {
+	return &RelayRepo{db: d.db}
+}
+
+func (r *RelayRepo) StartSession(ctx context.Context, telegramID int64, meetingID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO relay_sessions (telegram_id, meeting_id)
+		VALUES ($1, $2)
+		ON CONFLICT (telegram_id) DO UPDATE SET meeting_id = EXCLUDED.meeting_id, started_at = NOW()`,
+		telegramID, meetingID)
+	return err
+}
+
+// GetSession returns the meeting the user is chatting about, or 0 if they are not in a chat.
+func (r *RelayRepo) GetSession(ctx context.Context, telegramID int64) (int64, error) {
+	var meetingID int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT meeting_id FROM relay_sessions WHERE telegram_id = $1`, telegramID).Scan(&meetingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return meetingID, err
+}
+
+func (r *RelayRepo) EndSession(ctx context.Context, telegramID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM relay_sessions WHERE telegram_id = $1`, telegramID)
+	return err
+}
+
+func (r *RelayRepo) SaveMessage(ctx context.Context, m *domain.RelayMessage) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO relay_messages (meeting_id, sender_id, recipient_id, text)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		m.MeetingID, m.SenderID, m.RecipientID, m.Text,
+	).Scan(&m.ID, &m.CreatedAt)
+}
+
+func (r *RelayRepo) CountMessagesSince(ctx context.Context, meetingID int64, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM relay_messages
+		WHERE meeting_id = $1 AND created_at >= $2`, meetingID, since).Scan(&count)
+	return count, err
+}
+
+// GetMessages returns the latest relayed messages of the meeting in chronological order.
+func (r *RelayRepo) GetMessages(ctx context.Context, meetingID int64, limit int) ([]domain.RelayMessage, error) {
+	return r.queryMessages(ctx, `
+		SELECT id, meeting_id, sender_id, recipient_id, text, created_at FROM (
+			SELECT * FROM relay_messages
+			WHERE meeting_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		) latest ORDER BY created_at, id`, meetingID, limit)
+}
+
+// GetUserMessages returns the latest relayed messages sent or received by the user in chronological order.
+func (r *RelayRepo) GetUserMessages(ctx context.Context, telegramID int64, limit int) ([]domain.RelayMessage, error) {
+	return r.queryMessages(ctx, `
+		SELECT id, meeting_id, sender_id, recipient_id, text, created_at FROM (
+			SELECT * FROM relay_messages
+			WHERE sender_id = $1 OR recipient_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		) latest ORDER BY created_at, id`, telegramID, limit)
+}
+
+func (r *RelayRepo) queryMessages(ctx context.Context, query string, args ...any) ([]domain.RelayMessage, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.RelayMessage
+	for rows.Next() {
+		var m domain.RelayMessage
+		if err := rows.Scan(&m.ID, &m.MeetingID, &m.SenderID, &m.RecipientID, &m.Text, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+// participations lists every meeting from both the current event and the archive
+// once per participant, with the partner's "can't find" flag as a report against them.
+// Reports upheld by moderators come as extra rows with no meeting.
+const participations = `
+	SELECT dill_id AS telegram_id, time, dill_state AS state, dill_cancelled_at AS cancelled_at, doe_cant_find AS reported FROM meetings
+	UNION ALL
+	SELECT doe_id, time, doe_state, doe_cancelled_at, dill_cant_find FROM meetings
+	UNION ALL
+	SELECT dill_id, time, dill_state, dill_cancelled_at, doe_cant_find FROM meetings_archive
+	UNION ALL
+	SELECT doe_id, time, doe_state, doe_cancelled_at, dill_cant_find FROM meetings_archive
+	UNION ALL
+	SELECT reported_id, NULL, NULL, NULL, TRUE FROM reports WHERE status IN ('warned', 'banned')`
+
+const reliabilityColumns = `
+	COUNT(*) FILTER (WHERE state = 'arrived') AS arrived,
+	COUNT(*) FILTER (WHERE state = 'confirmed' AND time < NOW() - $1::interval) AS no_shows,
+	COUNT(*) FILTER (WHERE state = 'cancelled' AND cancelled_at > time - $2::interval) AS late_cancels,
+	COUNT(*) FILTER (WHERE reported) AS reports`
+
+type ReliabilityRepo struct {
+	db *sql.DB
+}
+
+func NewReliabilityRepo(d *DB) *ReliabilityRepo {
+	return &ReliabilityRepo{db: d.db}
+}
+
+func (r *ReliabilityRepo) GetReliability(ctx context.Context, telegramID int64, noShowAfter, lateCancelWithin time.Duration) (domain.Reliability, error) {
+	noShowSecs := fmt.Sprintf("%ds", int(noShowAfter.Seconds()))
+	lateSecs := fmt.Sprintf("%ds", int(lateCancelWithin.Seconds()))
+
+	var rel domain.Reliability
+	err := r.db.QueryRowContext(ctx, `
+		SELECT `+reliabilityColumns+`
+		FROM (`+participations+`) p
+		WHERE telegram_id = $3`,
+		noShowSecs, lateSecs, telegramID).
+		Scan(&rel.Arrived, &rel.NoShows, &rel.LateCancels, &rel.Reports)
+	if err != nil {
+		return domain.Reliability{}, err
+	}
+	return rel, nil
+}
+
+func (r *ReliabilityRepo) GetAllReliability(ctx context.Context, noShowAfter, lateCancelWithin time.Duration) (map[int64]domain.Reliability, error) {
+	noShowSecs := fmt.Sprintf("%ds", int(noShowAfter.Seconds()))
+	lateSecs := fmt.Sprintf("%ds", int(lateCancelWithin.Seconds()))
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT telegram_id, `+reliabilityColumns+`
+		FROM (`+participations+`) p
+		GROUP BY telegram_id`,
+		noShowSecs, lateSecs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]domain.Reliability)
+	for rows.Next() {
+		var (
+			id  int64
+			rel domain.Reliability
+		)
+		if err := rows.Scan(&id, &rel.Arrived, &rel.NoShows, &rel.LateCancels, &rel.Reports); err != nil {
+			return nil, err
+		}
+		result[id] = rel
+	}
+	return result, rows.Err()
+}
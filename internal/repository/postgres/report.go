@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+const reportColumns = `id, meeting_id, reporter_id, reported_id, reason, status, resolved_by, created_at, resolved_at`
+
+type ReportRepo struct {
+	db *sql.DB
+}
+
+func NewReportRepo(d *DB) *ReportRepo {
+	return &ReportRepo{db: d.db}
+}
+
+func (r *ReportRepo) CreateReport(ctx context.Context, report *domain.Report) (bool, error) {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO reports (meeting_id, reporter_id, reported_id, reason)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (meeting_id, reporter_id) DO NOTHING
+		RETURNING id, status, created_at`,
+		report.MeetingID, report.ReporterID, report.ReportedID, report.Reason,
+	).Scan(&report.ID, &report.Status, &report.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *ReportRepo) GetReport(ctx context.Context, id int64) (*domain.Report, error) {
+	return scanReport(r.db.QueryRowContext(ctx, `
+		SELECT `+reportColumns+` FROM reports WHERE id = $1`, id))
+}
+
+func (r *ReportRepo) GetNextPendingReport(ctx context.Context) (*domain.Report, error) {
+	return scanReport(r.db.QueryRowContext(ctx, `
+		SELECT `+reportColumns+` FROM reports
+		WHERE status = 'pending'
+		ORDER BY created_at LIMIT 1`))
+}
+
+func (r *ReportRepo) CountPendingReports(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM reports WHERE status = 'pending'`).Scan(&count)
+	return count, err
+}
+
+func (r *ReportRepo) CountReportsAgainst(ctx context.Context, telegramID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM reports WHERE reported_id = $1`, telegramID).Scan(&count)
+	return count, err
+}
+
+func (r *ReportRepo) ResolveReport(ctx context.Context, id int64, status domain.ReportStatus, adminID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE reports SET status = $1, resolved_by = $2, resolved_at = NOW()
+		WHERE id = $3 AND status = 'pending'`, status, adminID, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func scanReport(row *sql.Row) (*domain.Report, error) {
+	var report domain.Report
+	err := row.Scan(
+		&report.ID, &report.MeetingID, &report.ReporterID, &report.ReportedID,
+		&report.Reason, &report.Status, &report.ResolvedBy, &report.CreatedAt, &report.ResolvedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
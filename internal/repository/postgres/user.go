@@ -38,7 +38,7 @@ func (r *UserRepo) SaveUser(ctx context.Context, u *domain.User) error {
 func (r *UserRepo) GetUser(ctx context.Context, telegramID int64) (*domain.User, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT telegram_id, username, first_name, last_name, is_bot,
-		       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+		       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 		       referral_code, referrer_id, created_at
 		FROM users WHERE telegram_id = $1`, telegramID)
 	return scanUser(row)
@@ -47,7 +47,7 @@ func (r *UserRepo) GetUser(ctx context.Context, telegramID int64) (*domain.User,
 func (r *UserRepo) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT telegram_id, username, first_name, last_name, is_bot,
-		       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+		       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 		       referral_code, referrer_id, created_at
 		FROM users WHERE username = $1`, username)
 	return scanUser(row)
@@ -56,7 +56,7 @@ func (r *UserRepo) GetUserByUsername(ctx context.Context, username string) (*dom
 func (r *UserRepo) GetUserByReferralCode(ctx context.Context, code string) (*domain.User, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT telegram_id, username, first_name, last_name, is_bot,
-		       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+		       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 		       referral_code, referrer_id, created_at
 		FROM users WHERE referral_code = $1`, code)
 	return scanUser(row)
@@ -75,14 +75,37 @@ func (r *UserRepo) GetUserState(ctx context.Context, telegramID int64) (domain.U
 func (r *UserRepo) SetUserState(ctx context.Context, telegramID int64, state domain.UserState) error {
 	if state == domain.UserStateCompleted {
 		_, err := r.db.ExecContext(ctx,
-			`UPDATE users SET state = $1, is_registered = TRUE WHERE telegram_id = $2`, state, telegramID)
+			`UPDATE users SET state = $1, return_to = NULL, is_registered = TRUE WHERE telegram_id = $2`, state, telegramID)
 		return err
 	}
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET state = $1, return_to = NULL WHERE telegram_id = $2`, state, telegramID)
+	return err
+}
+
+func (r *UserRepo) BeginEdit(ctx context.Context, telegramID int64, state domain.UserState) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET return_to = COALESCE(return_to, state), state = $1 WHERE telegram_id = $2`, state, telegramID)
+	return err
+}
+
+func (r *UserRepo) SetStep(ctx context.Context, telegramID int64, state domain.UserState) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE users SET state = $1 WHERE telegram_id = $2`, state, telegramID)
 	return err
 }
 
+func (r *UserRepo) FinishEdit(ctx context.Context, telegramID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET state = return_to, return_to = NULL WHERE telegram_id = $1 AND return_to IS NOT NULL`, telegramID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 func (r *UserRepo) SetUserSex(ctx context.Context, telegramID int64, sex string) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE users SET sex = $1 WHERE telegram_id = $2`, sex, telegramID)
@@ -120,6 +143,43 @@ func (r *UserRepo) IsAdmin(ctx context.Context, telegramID int64) (bool, error)
 	return isAdmin, err
 }
 
+func (r *UserRepo) IsBanned(ctx context.Context, telegramID int64) (bool, error) {
+	var banned bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT banned FROM users WHERE telegram_id = $1`, telegramID).Scan(&banned)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return banned, err
+}
+
+func (r *UserRepo) SetBanned(ctx context.Context, telegramID int64, banned bool) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET banned = $1 WHERE telegram_id = $2`, banned, telegramID)
+	return err
+}
+
+func (r *UserRepo) SetUnreachable(ctx context.Context, telegramID int64, unreachable bool) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET unreachable = $1 WHERE telegram_id = $2 AND unreachable <> $1`, unreachable, telegramID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *UserRepo) IsUnreachable(ctx context.Context, telegramID int64) (bool, error) {
+	var unreachable bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT unreachable FROM users WHERE telegram_id = $1`, telegramID).Scan(&unreachable)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return unreachable, err
+}
+
 func (r *UserRepo) SetAdmin(ctx context.Context, telegramID int64, isAdmin bool) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE users SET is_admin = $1 WHERE telegram_id = $2`, isAdmin, telegramID)
@@ -141,9 +201,9 @@ func (r *UserRepo) SetReferrer(ctx context.Context, telegramID int64, referrerID
 func (r *UserRepo) GetVerifiedUsers(ctx context.Context) ([]domain.User, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT telegram_id, username, first_name, last_name, is_bot,
-		       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+		       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 		       referral_code, referrer_id, created_at
-		FROM users WHERE is_registered = TRUE AND opted_out = FALSE`)
+		FROM users WHERE is_registered = TRUE AND opted_out = FALSE AND banned = FALSE AND unreachable = FALSE`)
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +223,7 @@ func (r *UserRepo) GetVerifiedUsers(ctx context.Context) ([]domain.User, error)
 func (r *UserRepo) GetAdmins(ctx context.Context) ([]domain.User, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT telegram_id, username, first_name, last_name, is_bot,
-		       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+		       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 		       referral_code, referrer_id, created_at
 		FROM users WHERE is_admin = true`)
 	if err != nil {
@@ -203,7 +263,7 @@ func scanUser(row *sql.Row) (*domain.User, error) {
 	err := row.Scan(
 		&u.TelegramID, &username, &firstName, &lastName,
 		&u.IsBot, &languageCode, &u.IsPremium, &sex, &u.About,
-		&u.State, &u.RegistrationNotified, &u.InviteNotified, &u.TimeRanges, &u.IsAdmin, &u.OptedOut, &u.IsRegistered,
+		&u.State, &u.TimeRanges, &u.IsAdmin, &u.OptedOut, &u.IsRegistered,
 		&referralCode, &referrerID, &u.CreatedAt,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
@@ -234,7 +294,7 @@ func scanUserFromRows(rows *sql.Rows) (*domain.User, error) {
 	err := rows.Scan(
 		&u.TelegramID, &username, &firstName, &lastName,
 		&u.IsBot, &languageCode, &u.IsPremium, &sex, &u.About,
-		&u.State, &u.RegistrationNotified, &u.InviteNotified, &u.TimeRanges, &u.IsAdmin, &u.OptedOut, &u.IsRegistered,
+		&u.State, &u.TimeRanges, &u.IsAdmin, &u.OptedOut, &u.IsRegistered,
 		&referralCode, &referrerID, &u.CreatedAt,
 	)
 	if err != nil {
@@ -297,17 +357,18 @@ func (r *UserRepo) GetReferralLeaderboard(ctx context.Context) ([]domain.Referra
 	return leaderboard, rows.Err()
 }
 
-func (r *UserRepo) MarkNotified(ctx context.Context, telegramID int64) error {
-	_, err := r.db.ExecContext(ctx, `UPDATE users SET registration_notified = TRUE WHERE telegram_id = $1`, telegramID)
-	return err
-}
-
 func (r *UserRepo) GetNotCompleted(ctx context.Context, interval time.Duration) ([]domain.User, error) {
 	secs := fmt.Sprintf("%ds", int(interval.Seconds()))
 	rows, err := r.db.QueryContext(ctx, `SELECT telegram_id, username, first_name, last_name, is_bot,
-	       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+	       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 	       referral_code, referrer_id, created_at
-	FROM users WHERE now() - created_at > $1::interval AND registration_notified = FALSE AND state <> 'completed'`, secs)
+	FROM users WHERE now() - created_at > $1::interval AND state <> 'completed' AND banned = FALSE AND unreachable = FALSE
+	  AND NOT EXISTS (
+	      SELECT 1 FROM deliveries d
+	      WHERE d.kind = $2 AND d.recipient = users.telegram_id AND `+delivered("d")+`)
+	  AND NOT EXISTS (
+	      SELECT 1 FROM notification_mutes m
+	      WHERE m.telegram_id = users.telegram_id AND m.category = $3)`, secs, string(domain.DeliveryRegistrationReminder), string(domain.NotificationRegistrationReminder))
 	if err != nil {
 		return nil, err
 	}
@@ -327,9 +388,15 @@ func (r *UserRepo) GetNotCompleted(ctx context.Context, interval time.Duration)
 func (r *UserRepo) GetForInviteReminder(ctx context.Context, interval time.Duration) ([]domain.User, error) {
 	secs := fmt.Sprintf("%ds", int(interval.Seconds()))
 	rows, err := r.db.QueryContext(ctx, `SELECT telegram_id, username, first_name, last_name, is_bot,
-	       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+	       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 	       referral_code, referrer_id, created_at
-	FROM users WHERE now() - created_at > $1::interval AND invite_notified = FALSE AND is_admin = FALSE`, secs)
+	FROM users WHERE now() - created_at > $1::interval AND is_admin = FALSE AND banned = FALSE AND unreachable = FALSE
+	  AND NOT EXISTS (
+	      SELECT 1 FROM deliveries d
+	      WHERE d.kind = $2 AND d.recipient = users.telegram_id AND `+delivered("d")+`)
+	  AND NOT EXISTS (
+	      SELECT 1 FROM notification_mutes m
+	      WHERE m.telegram_id = users.telegram_id AND m.category = $3)`, secs, string(domain.DeliveryInviteReminder), string(domain.NotificationInviteReminder))
 	if err != nil {
 		return nil, err
 	}
@@ -346,11 +413,6 @@ func (r *UserRepo) GetForInviteReminder(ctx context.Context, interval time.Durat
 	return users, rows.Err()
 }
 
-func (r *UserRepo) MarkInviteNotified(ctx context.Context, telegramID int64) error {
-	_, err := r.db.ExecContext(ctx, `UPDATE users SET invite_notified = TRUE WHERE telegram_id = $1`, telegramID)
-	return err
-}
-
 func (r *UserRepo) SetOptedOut(ctx context.Context, telegramID int64, optedOut bool) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE users SET opted_out = $1 WHERE telegram_id = $2`, optedOut, telegramID)
@@ -385,9 +447,9 @@ func (r *UserRepo) GetSexCounts(ctx context.Context) (males uint, females uint,
 func (r *UserRepo) GetUnregisteredUsers(ctx context.Context) ([]domain.User, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT telegram_id, username, first_name, last_name, is_bot,
-		       language_code, is_premium, sex, about, state, registration_notified, invite_notified, time_ranges, is_admin, opted_out, is_registered,
+		       language_code, is_premium, sex, about, state, time_ranges, is_admin, opted_out, is_registered,
 		       referral_code, referrer_id, created_at
-		FROM users WHERE is_registered = FALSE AND opted_out = FALSE AND is_admin = FALSE`)
+		FROM users WHERE is_registered = FALSE AND opted_out = FALSE AND is_admin = FALSE AND banned = FALSE AND unreachable = FALSE`)
 	if err != nil {
 		return nil, err
 	}
@@ -416,3 +478,40 @@ func (r *UserRepo) GetUserCounts(ctx context.Context) (total uint, registered ui
 	}
 	return total, registered, optedOut, nil
 }
+
+func (r *UserRepo) GetMutedCategories(ctx context.Context, telegramID int64) ([]domain.NotificationCategory, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT category FROM notification_mutes WHERE telegram_id = $1`, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []domain.NotificationCategory
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		categories = append(categories, domain.NotificationCategory(c))
+	}
+	return categories, rows.Err()
+}
+
+func (r *UserRepo) IsMuted(ctx context.Context, telegramID int64, category domain.NotificationCategory) (bool, error) {
+	var muted bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM notification_mutes WHERE telegram_id = $1 AND category = $2)`,
+		telegramID, string(category)).Scan(&muted)
+	return muted, err
+}
+
+func (r *UserRepo) SetMuted(ctx context.Context, telegramID int64, category domain.NotificationCategory, muted bool) error {
+	query := `DELETE FROM notification_mutes WHERE telegram_id = $1 AND category = $2`
+	if muted {
+		query = `INSERT INTO notification_mutes (telegram_id, category) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+
+	_, err := r.db.ExecContext(ctx, query, telegramID, string(category))
+	return err
+}
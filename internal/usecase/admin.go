@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
+	"strings"
 
 	"github.com/jus1d/kypidbot/internal/config/messages"
 	"github.com/jus1d/kypidbot/internal/domain"
@@ -16,12 +18,16 @@ var (
 )
 
 type Admin struct {
-	users    domain.UserRepository
-	meetings domain.MeetingRepository
+	users       domain.UserRepository
+	meetings    domain.MeetingRepository
+	ratings     domain.RatingRepository
+	outbox      domain.OutboxRepository
+	jobs        domain.JobRepository
+	reliability *Reliability
 }
 
-func NewAdmin(users domain.UserRepository, meetings domain.MeetingRepository) *Admin {
-	return &Admin{users: users, meetings: meetings}
+func NewAdmin(users domain.UserRepository, meetings domain.MeetingRepository, ratings domain.RatingRepository, outbox domain.OutboxRepository, jobs domain.JobRepository, reliability *Reliability) *Admin {
+	return &Admin{users: users, meetings: meetings, ratings: ratings, outbox: outbox, jobs: jobs, reliability: reliability}
 }
 
 func (a *Admin) Promote(ctx context.Context, username string) error {
@@ -73,6 +79,16 @@ func (a *Admin) GetStatistics(ctx context.Context) (domain.Statistics, error) {
 		return domain.Statistics{}, err
 	}
 
+	ratingStats, err := a.ratings.GetRatingStats(ctx)
+	if err != nil {
+		return domain.Statistics{}, err
+	}
+
+	outboxStats, err := a.outbox.GetOutboxStats(ctx)
+	if err != nil {
+		return domain.Statistics{}, err
+	}
+
 	return domain.Statistics{
 		TotalUsers:       total,
 		RegisteredUsers:  registered,
@@ -82,6 +98,8 @@ func (a *Admin) GetStatistics(ctx context.Context) (domain.Statistics, error) {
 		MaleCount:        males,
 		FemaleCount:      females,
 		Meetings:         meetingStats,
+		Ratings:          ratingStats,
+		Outbox:           outboxStats,
 	}, nil
 }
 
@@ -93,6 +111,11 @@ func (a *Admin) FormatPanel(ctx context.Context) (string, error) {
 
 	active := s.RegisteredUsers - s.OptedOutUsers
 
+	tags := make([]string, 0, len(domain.RatingTags))
+	for _, tag := range domain.RatingTags {
+		tags = append(tags, fmt.Sprintf("- %s: %d", messages.M.Rating.TagLabels[string(tag)], s.Ratings.Tags[tag]))
+	}
+
 	return messages.Format(messages.M.Command.Admin, map[string]string{
 		"total_users":       fmt.Sprintf("%d", s.TotalUsers),
 		"registered_users":  fmt.Sprintf("%d", s.RegisteredUsers),
@@ -106,5 +129,86 @@ func (a *Admin) FormatPanel(ctx context.Context) (string, error) {
 		"meetings_confirmed": fmt.Sprintf("%d", s.Meetings.Confirmed),
 		"meetings_cancelled": fmt.Sprintf("%d", s.Meetings.Cancelled),
 		"meetings_pending":  fmt.Sprintf("%d", s.Meetings.Pending),
+		"meetings_answered": fmt.Sprintf("%d", s.Meetings.Answered),
+		"meetings_mutual":   fmt.Sprintf("%d", s.Meetings.Mutual),
+		"ratings_count":     fmt.Sprintf("%d", s.Ratings.Count),
+		"ratings_average":   fmt.Sprintf("%.2f", s.Ratings.Average),
+		"ratings_tags":      strings.Join(tags, "\n"),
+		"outbox_pending":    fmt.Sprintf("%d", s.Outbox.Pending),
+		"outbox_sent":       fmt.Sprintf("%d", s.Outbox.Sent),
+		"outbox_failed":     fmt.Sprintf("%d", s.Outbox.Failed),
+	}), nil
+}
+
+// FormatUser formats the user's profile together with their reliability for admins.
+func (a *Admin) FormatUser(ctx context.Context, username string) (string, error) {
+	user, err := a.users.GetUserByUsername(ctx, username)
+	if err != nil {
+		return "", fmt.Errorf("get user: %w", err)
+	}
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+
+	rel, err := a.reliability.Get(ctx, user.TelegramID)
+	if err != nil {
+		return "", err
+	}
+
+	status := messages.M.Admin.User.Matched
+	if a.reliability.Excluded(rel) {
+		status = messages.M.Admin.User.Excluded
+	}
+
+	return messages.Format(messages.M.Admin.User.Card, map[string]string{
+		"mention":      messages.Mention(user.TelegramID, user.FirstName, user.Username),
+		"id":           fmt.Sprintf("%d", user.TelegramID),
+		"state":        string(user.State),
+		"arrived":      fmt.Sprintf("%d", rel.Arrived),
+		"no_shows":     fmt.Sprintf("%d", rel.NoShows),
+		"late_cancels": fmt.Sprintf("%d", rel.LateCancels),
+		"reports":      fmt.Sprintf("%d", rel.Reports),
+		"penalty":      fmt.Sprintf("%d", rel.Penalty()),
+		"status":       status,
 	}), nil
 }
+
+// FormatJobs formats the last run of every notification job for admins.
+func (a *Admin) FormatJobs(ctx context.Context) (string, error) {
+	jobs, err := a.jobs.GetJobs(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get jobs: %w", err)
+	}
+
+	if len(jobs) == 0 {
+		return messages.M.Admin.Jobs.Empty, nil
+	}
+
+	items := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		lastRun := messages.M.Admin.Jobs.Never
+		if j.LastRunAt != nil {
+			lastRun = domain.Timef(*j.LastRunAt)
+		}
+
+		var status string
+		switch {
+		case j.LastRunAt == nil:
+		case j.LastFinishedAt == nil || j.LastFinishedAt.Before(*j.LastRunAt):
+			status = messages.M.Admin.Jobs.Running
+		case j.LastError != "":
+			status = messages.Format(messages.M.Admin.Jobs.Failed, map[string]string{"error": html.EscapeString(j.LastError)})
+		default:
+			status = messages.M.Admin.Jobs.OK
+		}
+
+		items = append(items, messages.Format(messages.M.Admin.Jobs.Item, map[string]string{
+			"name":     j.Name,
+			"schedule": j.Schedule,
+			"last_run": lastRun,
+			"status":   status,
+		}))
+	}
+
+	return messages.M.Admin.Jobs.Header + "\n\n" + strings.Join(items, "\n\n"), nil
+}
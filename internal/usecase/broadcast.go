@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var (
+	ErrNoDraft             = errors.New("no broadcast draft")
+	ErrBroadcastNotFound   = errors.New("broadcast not found")
+	ErrBroadcastNotDraft   = errors.New("broadcast is already sent or cancelled")
+	ErrInvalidSegment      = errors.New("invalid broadcast segment")
+	ErrInvalidScheduleTime = errors.New("invalid broadcast time")
+	ErrNoRecipients        = errors.New("no broadcast recipients")
+)
+
+// scheduleLayout is how admins type the time of a scheduled broadcast.
+const scheduleLayout = "02.01 15:04"
+
+type Broadcasts struct {
+	users      domain.UserRepository
+	broadcasts domain.BroadcastRepository
+}
+
+func NewBroadcasts(users domain.UserRepository, broadcasts domain.BroadcastRepository) *Broadcasts {
+	return &Broadcasts{users: users, broadcasts: broadcasts}
+}
+
+// Compose starts a new broadcast draft and waits for the author's message.
+// The author's unfinished draft, if any, is dropped.
+func (b *Broadcasts) Compose(ctx context.Context, authorID int64) (*domain.Broadcast, error) {
+	draft, err := b.broadcasts.GetDraft(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("get draft: %w", err)
+	}
+	if draft != nil {
+		if _, err := b.broadcasts.Cancel(ctx, draft.ID); err != nil {
+			return nil, fmt.Errorf("cancel draft: %w", err)
+		}
+	}
+
+	broadcast, err := b.broadcasts.CreateBroadcast(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("create broadcast: %w", err)
+	}
+
+	if err := b.users.SetUserState(ctx, authorID, domain.UserStateAwaitingBroadcast); err != nil {
+		return nil, fmt.Errorf("set user state: %w", err)
+	}
+	return broadcast, nil
+}
+
+// SetContent makes the author's message in chatID the content of their draft.
+func (b *Broadcasts) SetContent(ctx context.Context, authorID int64, chatID int64, messageID int) (*domain.Broadcast, error) {
+	draft, err := b.draft(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.broadcasts.SetContent(ctx, draft.ID, chatID, messageID); err != nil {
+		return nil, fmt.Errorf("set content: %w", err)
+	}
+	draft.SourceChatID = chatID
+	draft.SourceMessageID = messageID
+
+	if err := b.users.SetUserState(ctx, authorID, domain.UserStateCompleted); err != nil {
+		return nil, fmt.Errorf("set user state: %w", err)
+	}
+	return draft, nil
+}
+
+// ChooseSegment sets the audience of the draft and returns the number of its recipients.
+func (b *Broadcasts) ChooseSegment(ctx context.Context, id int64, segment domain.BroadcastSegment) (*domain.Broadcast, int, error) {
+	if !segment.Valid() {
+		return nil, 0, ErrInvalidSegment
+	}
+
+	broadcast, err := b.get(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if broadcast.Status != domain.BroadcastStatusDraft {
+		return nil, 0, ErrBroadcastNotDraft
+	}
+
+	if err := b.broadcasts.SetSegment(ctx, id, segment); err != nil {
+		return nil, 0, fmt.Errorf("set segment: %w", err)
+	}
+	broadcast.Segment = segment
+
+	count, err := b.broadcasts.CountRecipients(ctx, segment)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count recipients: %w", err)
+	}
+	return broadcast, count, nil
+}
+
+// Send queues the broadcast to its recipients right away.
+func (b *Broadcasts) Send(ctx context.Context, id int64) (*domain.Broadcast, error) {
+	broadcast, err := b.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if broadcast.Segment == "" {
+		return nil, ErrInvalidSegment
+	}
+
+	count, err := b.broadcasts.CountRecipients(ctx, broadcast.Segment)
+	if err != nil {
+		return nil, fmt.Errorf("count recipients: %w", err)
+	}
+	if count == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	return b.start(ctx, id)
+}
+
+// Draft returns the author's broadcast draft, or nil if there is none.
+func (b *Broadcasts) Draft(ctx context.Context, authorID int64) (*domain.Broadcast, error) {
+	return b.broadcasts.GetDraft(ctx, authorID)
+}
+
+// AskTime waits for the author to type when the draft should be sent.
+func (b *Broadcasts) AskTime(ctx context.Context, authorID int64, id int64) error {
+	draft, err := b.draft(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	if draft.ID != id {
+		return ErrBroadcastNotDraft
+	}
+
+	return b.users.SetUserState(ctx, authorID, domain.UserStateAwaitingBroadcastTime)
+}
+
+// Schedule schedules the author's draft for the time typed as "DD.MM HH:MM"
+// in the event's timezone.
+func (b *Broadcasts) Schedule(ctx context.Context, authorID int64, text string) (*domain.Broadcast, error) {
+	draft, err := b.draft(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation("Europe/Samara")
+	if err != nil {
+		return nil, fmt.Errorf("load location: %w", err)
+	}
+
+	at, err := time.ParseInLocation(scheduleLayout, strings.TrimSpace(text), loc)
+	if err != nil {
+		return nil, ErrInvalidScheduleTime
+	}
+	now := time.Now().In(loc)
+	at = at.AddDate(now.Year(), 0, 0)
+	if !at.After(now) {
+		return nil, ErrInvalidScheduleTime
+	}
+
+	ok, err := b.broadcasts.Schedule(ctx, draft.ID, at)
+	if err != nil {
+		return nil, fmt.Errorf("schedule broadcast: %w", err)
+	}
+	if !ok {
+		return nil, ErrBroadcastNotDraft
+	}
+	draft.Status = domain.BroadcastStatusScheduled
+	draft.ScheduledAt = &at
+
+	if err := b.users.SetUserState(ctx, authorID, domain.UserStateCompleted); err != nil {
+		return nil, fmt.Errorf("set user state: %w", err)
+	}
+	return draft, nil
+}
+
+// Cancel cancels a draft or scheduled broadcast.
+func (b *Broadcasts) Cancel(ctx context.Context, authorID int64, id int64) error {
+	ok, err := b.broadcasts.Cancel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("cancel broadcast: %w", err)
+	}
+	if !ok {
+		return ErrBroadcastNotDraft
+	}
+
+	return b.users.SetUserState(ctx, authorID, domain.UserStateCompleted)
+}
+
+// StartDue queues the scheduled broadcasts whose time has come and returns them.
+func (b *Broadcasts) StartDue(ctx context.Context) ([]domain.Broadcast, error) {
+	due, err := b.broadcasts.GetDueScheduled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get due broadcasts: %w", err)
+	}
+
+	var started []domain.Broadcast
+	for _, d := range due {
+		broadcast, err := b.start(ctx, d.ID)
+		if errors.Is(err, ErrBroadcastNotDraft) {
+			continue
+		}
+		if err != nil {
+			return started, err
+		}
+		started = append(started, *broadcast)
+	}
+	return started, nil
+}
+
+// Sending returns the broadcasts being delivered.
+func (b *Broadcasts) Sending(ctx context.Context) ([]domain.Broadcast, error) {
+	return b.broadcasts.GetSending(ctx)
+}
+
+// Progress returns the delivery progress of the broadcast and finishes it
+// once nothing is left in the queue.
+func (b *Broadcasts) Progress(ctx context.Context, broadcast *domain.Broadcast) (domain.OutboxStats, error) {
+	stats, err := b.broadcasts.GetBroadcastProgress(ctx, broadcast.ID)
+	if err != nil {
+		return domain.OutboxStats{}, fmt.Errorf("get broadcast progress: %w", err)
+	}
+
+	if stats.Pending == 0 {
+		if err := b.broadcasts.Finish(ctx, broadcast.ID); err != nil {
+			return domain.OutboxStats{}, fmt.Errorf("finish broadcast: %w", err)
+		}
+		broadcast.Status = domain.BroadcastStatusDone
+	}
+	return stats, nil
+}
+
+func (b *Broadcasts) SetProgressMessage(ctx context.Context, id int64, messageID int) error {
+	return b.broadcasts.SetProgressMessage(ctx, id, messageID)
+}
+
+// FormatPreview formats the card shown under the broadcast preview.
+func (b *Broadcasts) FormatPreview(broadcast *domain.Broadcast, count int) string {
+	return messages.Format(messages.M.Broadcast.Preview, map[string]string{
+		"segment": messages.M.Broadcast.Segments[string(broadcast.Segment)],
+		"count":   fmt.Sprintf("%d", count),
+	})
+}
+
+// FormatProgress formats the live progress message of the broadcast.
+func (b *Broadcasts) FormatProgress(broadcast *domain.Broadcast, stats domain.OutboxStats) string {
+	template := messages.M.Broadcast.Progress
+	if broadcast.Status == domain.BroadcastStatusDone {
+		template = messages.M.Broadcast.Done
+	}
+
+	return messages.Format(template, map[string]string{
+		"id":      fmt.Sprintf("%d", broadcast.ID),
+		"segment": messages.M.Broadcast.Segments[string(broadcast.Segment)],
+		"total":   fmt.Sprintf("%d", broadcast.Total),
+		"sent":    fmt.Sprintf("%d", stats.Sent),
+		"failed":  fmt.Sprintf("%d", stats.Failed),
+		"pending": fmt.Sprintf("%d", stats.Pending),
+	})
+}
+
+func (b *Broadcasts) start(ctx context.Context, id int64) (*domain.Broadcast, error) {
+	ok, err := b.broadcasts.Start(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("start broadcast: %w", err)
+	}
+	if !ok {
+		return nil, ErrBroadcastNotDraft
+	}
+
+	return b.get(ctx, id)
+}
+
+func (b *Broadcasts) get(ctx context.Context, id int64) (*domain.Broadcast, error) {
+	broadcast, err := b.broadcasts.GetBroadcast(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get broadcast: %w", err)
+	}
+	if broadcast == nil {
+		return nil, ErrBroadcastNotFound
+	}
+	return broadcast, nil
+}
+
+func (b *Broadcasts) draft(ctx context.Context, authorID int64) (*domain.Broadcast, error) {
+	draft, err := b.broadcasts.GetDraft(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("get draft: %w", err)
+	}
+	if draft == nil {
+		return nil, ErrNoDraft
+	}
+	return draft, nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+	"github.com/jus1d/kypidbot/internal/lib/ics"
+)
+
+const (
+	calendarDuration = time.Hour
+	calendarAlarm    = time.Hour
+)
+
+// Calendar returns the meeting as an iCalendar file. With cancelled set, the file
+// cancels the event sent earlier for the same meeting.
+//
+// The sequence follows the meeting's revision, so the cancellation of a revision
+// outranks its event, and the event of the next revision outranks both.
+func (m *Meeting) Calendar(ctx context.Context, meeting *domain.Meeting, cancelled bool) ([]byte, error) {
+	if meeting.PlaceID == nil || meeting.Time == nil {
+		return nil, ErrMeetingUnavailable
+	}
+
+	place, err := m.places.GetPlace(ctx, *meeting.PlaceID)
+	if err != nil {
+		return nil, fmt.Errorf("get place: %w", err)
+	}
+	if place == nil {
+		return nil, ErrPlaceNotFound
+	}
+
+	method, sequence := ics.MethodPublish, 2*meeting.Revision
+	if cancelled {
+		method, sequence = ics.MethodCancel, 2*meeting.Revision+1
+	}
+
+	return ics.Marshal(method, ics.Event{
+		UID:         fmt.Sprintf("meeting-%d@kypidbot", meeting.ID),
+		Sequence:    sequence,
+		Start:       *meeting.Time,
+		Duration:    calendarDuration,
+		Summary:     messages.M.Meeting.Calendar.Summary,
+		Location:    place.Description,
+		Description: place.Route,
+		Latitude:    place.Latitude,
+		Longitude:   place.Longitude,
+		Alarm:       calendarAlarm,
+	}, time.Now()), nil
+}
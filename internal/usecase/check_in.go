@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var ErrNoPendingCheckIn = errors.New("no pending check-in")
+
+type CheckInResult struct {
+	MeetingID int64
+	PartnerID int64
+	// Distance is how far in meters the participant is from the place.
+	Distance float64
+	Arrived  bool
+}
+
+// StartCheckIn reports whether the participant's arrival can be verified by their location.
+// If so, the bot waits for the participant to share it. Fails with a *domain.TransitionError
+// if the participant cannot arrive at the meeting.
+func (m *Meeting) StartCheckIn(ctx context.Context, meetingID int64, telegramID int64) (bool, error) {
+	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return false, fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil || meeting.PlaceID == nil || (meeting.DillID != telegramID && meeting.DoeID != telegramID) {
+		return false, nil
+	}
+
+	state := meeting.DoeState
+	if meeting.DillID == telegramID {
+		state = meeting.DillState
+	}
+	if err := domain.CheckTransition(state, domain.StateArrived); err != nil {
+		return false, err
+	}
+
+	place, err := m.places.GetPlace(ctx, *meeting.PlaceID)
+	if err != nil {
+		return false, fmt.Errorf("get place: %w", err)
+	}
+	if place == nil || !place.HasLocation() {
+		return false, nil
+	}
+
+	if err := m.checkIns.StartCheckIn(ctx, meetingID, telegramID); err != nil {
+		return false, fmt.Errorf("start check-in: %w", err)
+	}
+	if err := m.users.SetUserState(ctx, telegramID, domain.UserStateAwaitingLocation); err != nil {
+		return false, fmt.Errorf("set state: %w", err)
+	}
+	return true, nil
+}
+
+// CheckIn verifies the shared location against the meeting place and marks
+// the participant as arrived once they are within the place's radius.
+func (m *Meeting) CheckIn(ctx context.Context, telegramID int64, lat, lon float64) (*CheckInResult, error) {
+	meetingID, err := m.checkIns.GetPendingCheckIn(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("get pending check-in: %w", err)
+	}
+	if meetingID == 0 {
+		return nil, ErrNoPendingCheckIn
+	}
+
+	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil || meeting.PlaceID == nil {
+		return nil, ErrNoPendingCheckIn
+	}
+
+	place, err := m.places.GetPlace(ctx, *meeting.PlaceID)
+	if err != nil {
+		return nil, fmt.Errorf("get place: %w", err)
+	}
+	if place == nil || !place.HasLocation() {
+		return nil, ErrNoPendingCheckIn
+	}
+
+	distance := domain.Distance(lat, lon, *place.Latitude, *place.Longitude)
+	arrived := distance <= float64(place.Radius)
+
+	if err := m.checkIns.SaveLocation(ctx, meetingID, telegramID, lat, lon, distance, arrived); err != nil {
+		return nil, fmt.Errorf("save location: %w", err)
+	}
+
+	if arrived {
+		if err := m.arrive(ctx, meeting, telegramID); err != nil {
+			return nil, err
+		}
+	}
+
+	partnerID := meeting.DoeID
+	if meeting.DoeID == telegramID {
+		partnerID = meeting.DillID
+	}
+
+	return &CheckInResult{
+		MeetingID: meetingID,
+		PartnerID: partnerID,
+		Distance:  distance,
+		Arrived:   arrived,
+	}, nil
+}
+
+// SkipCheckIn marks the participant as arrived without verifying their location,
+// for when they won't share it.
+func (m *Meeting) SkipCheckIn(ctx context.Context, telegramID int64) error {
+	meetingID, err := m.checkIns.GetPendingCheckIn(ctx, telegramID)
+	if err != nil {
+		return fmt.Errorf("get pending check-in: %w", err)
+	}
+
+	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil {
+		return ErrNoPendingCheckIn
+	}
+
+	return m.arrive(ctx, meeting, telegramID)
+}
+
+// arrive marks the participant as arrived. If they no longer can, e.g. because the
+// meeting was cancelled while they were on their way, the check-in is abandoned.
+func (m *Meeting) arrive(ctx context.Context, meeting *domain.Meeting, telegramID int64) error {
+	if _, err := m.transition(ctx, meeting, telegramID, domain.StateArrived); err != nil {
+		if domain.IsTransitionError(err) {
+			if err := m.users.SetUserState(ctx, telegramID, domain.UserStateCompleted); err != nil {
+				return fmt.Errorf("set state: %w", err)
+			}
+		}
+		return err
+	}
+	return m.users.SetUserState(ctx, telegramID, domain.UserStateAwaitingAppearance)
+}
@@ -13,9 +13,18 @@ type MatchResult struct {
 	PairsCount     int
 	FullMatchCount int
 	UsersCount     int
+	ExcludedCount  int
 	UnmatchedIDs   []int64
 }
 
+type RematchResult struct {
+	// Meetings are the new pairs, regular meetings and full matches, not saved yet.
+	Meetings    []domain.Meeting
+	FullMatches []FullMatchNotification
+	// Stranded are the meetings whose participants were pulled into the pool.
+	Stranded []domain.Meeting
+}
+
 type DryPair struct {
 	DillTelegramID int64
 	DillFirstName  string
@@ -26,38 +35,36 @@ type DryPair struct {
 }
 
 type Matching struct {
-	users    domain.UserRepository
-	meetings domain.MeetingRepository
-	ollama   *ollama.Client
+	users       domain.UserRepository
+	meetings    domain.MeetingRepository
+	blocks      domain.BlockRepository
+	reliability *Reliability
+	ollama      *ollama.Client
 }
 
-func NewMatching(users domain.UserRepository, meetings domain.MeetingRepository, c *ollama.Client) *Matching {
+func NewMatching(users domain.UserRepository, meetings domain.MeetingRepository, blocks domain.BlockRepository, reliability *Reliability, c *ollama.Client) *Matching {
 	return &Matching{
-		users:    users,
-		meetings: meetings,
-		ollama:   c,
+		users:       users,
+		meetings:    meetings,
+		blocks:      blocks,
+		reliability: reliability,
+		ollama:      c,
 	}
 }
 
 func (m *Matching) RunMatch(ctx context.Context) (*MatchResult, error) {
-	users, err := m.users.GetVerifiedUsers(ctx)
+	verified, err := m.users.GetVerifiedUsers(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get verified users: %w", err)
 	}
 
-	if len(users) < 2 {
-		return nil, fmt.Errorf("not enough users")
+	users, matchUsers, excluded, err := m.candidates(ctx, verified)
+	if err != nil {
+		return nil, err
 	}
 
-	matchUsers := make([]matcher.MatchUser, len(users))
-	for i, u := range users {
-		matchUsers[i] = matcher.MatchUser{
-			Index:      i,
-			Username:   u.Username,
-			Sex:        u.Sex,
-			About:      u.About,
-			TimeRanges: u.TimeRanges,
-		}
+	if len(users) < 2 {
+		return nil, fmt.Errorf("not enough users")
 	}
 
 	pairs, fullMatches, err := matcher.Match(matchUsers, m.ollama)
@@ -118,29 +125,24 @@ func (m *Matching) RunMatch(ctx context.Context) (*MatchResult, error) {
 		PairsCount:     len(pairs),
 		FullMatchCount: len(fullMatches),
 		UsersCount:     len(users),
+		ExcludedCount:  excluded,
 		UnmatchedIDs:   unmatchedIDs,
 	}, nil
 }
 
 func (m *Matching) DryMatch(ctx context.Context) ([]DryPair, error) {
-	users, err := m.users.GetVerifiedUsers(ctx)
+	verified, err := m.users.GetVerifiedUsers(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get verified users: %w", err)
 	}
 
-	if len(users) < 2 {
-		return nil, fmt.Errorf("not enough users")
+	users, matchUsers, _, err := m.candidates(ctx, verified)
+	if err != nil {
+		return nil, err
 	}
 
-	matchUsers := make([]matcher.MatchUser, len(users))
-	for i, u := range users {
-		matchUsers[i] = matcher.MatchUser{
-			Index:      i,
-			Username:   u.Username,
-			Sex:        u.Sex,
-			About:      u.About,
-			TimeRanges: u.TimeRanges,
-		}
+	if len(users) < 2 {
+		return nil, fmt.Errorf("not enough users")
 	}
 
 	pairs, fullMatches, err := matcher.Match(matchUsers, m.ollama)
@@ -172,3 +174,173 @@ func (m *Matching) DryMatch(ctx context.Context) ([]DryPair, error) {
 
 	return result, nil
 }
+
+// Rematch pulls participants whose partner cancelled or missed the confirmation
+// deadline back into a pool with the users who were left unmatched, and pairs
+// them up again. The new meetings are not saved: see Meeting.ScheduleRematch.
+// Returns nil if nobody is stranded.
+func (m *Matching) Rematch(ctx context.Context) (*RematchResult, error) {
+	stranded, err := m.meetings.GetStrandedMeetings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get stranded meetings: %w", err)
+	}
+
+	if len(stranded) == 0 {
+		return nil, nil
+	}
+
+	regularMeetings, err := m.meetings.GetRegularMeetings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get regular meetings: %w", err)
+	}
+
+	fullMeetings, err := m.meetings.GetFullMeetings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get full meetings: %w", err)
+	}
+
+	// only meetings nobody dropped out of keep their participants matched. Those
+	// who dropped out are done with the event, and so are the partners who were
+	// already told they could not be rematched.
+	matched := make(map[int64]bool)
+	out := make(map[int64]bool)
+	for _, mt := range regularMeetings {
+		if !mt.DillState.Dropped() && !mt.DoeState.Dropped() {
+			matched[mt.DillID] = true
+			matched[mt.DoeID] = true
+			continue
+		}
+		if mt.DillState.Dropped() || mt.Rematched {
+			out[mt.DillID] = true
+		}
+		if mt.DoeState.Dropped() || mt.Rematched {
+			out[mt.DoeID] = true
+		}
+	}
+	for _, mt := range fullMeetings {
+		matched[mt.DillID] = true
+		matched[mt.DoeID] = true
+	}
+
+	result := RematchResult{Stranded: stranded}
+	pool := make(map[int64]bool)
+	for _, mt := range stranded {
+		if id := mt.Stranded(); id != 0 {
+			pool[id] = true
+		}
+	}
+
+	verified, err := m.users.GetVerifiedUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get verified users: %w", err)
+	}
+
+	var pooled []domain.User
+	for _, u := range verified {
+		if pool[u.TelegramID] || (!matched[u.TelegramID] && !out[u.TelegramID]) {
+			pooled = append(pooled, u)
+		}
+	}
+
+	users, matchUsers, _, err := m.candidates(ctx, pooled)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) < 2 {
+		return &result, nil
+	}
+
+	pairs, fullMatches, err := matcher.Match(matchUsers, m.ollama)
+	if err != nil {
+		return nil, fmt.Errorf("match: %w", err)
+	}
+
+	for _, p := range pairs {
+		result.Meetings = append(result.Meetings, domain.Meeting{
+			DillID:      users[p.I].TelegramID,
+			DoeID:       users[p.J].TelegramID,
+			PairScore:   p.Score,
+			IsFullmatch: false,
+		})
+	}
+
+	for _, fm := range fullMatches {
+		dill := users[fm.I]
+		doe := users[fm.J]
+
+		result.Meetings = append(result.Meetings, domain.Meeting{
+			DillID:      dill.TelegramID,
+			DoeID:       doe.TelegramID,
+			PairScore:   fm.Score,
+			IsFullmatch: true,
+		})
+		result.FullMatches = append(result.FullMatches, FullMatchNotification{
+			DillTelegramID: dill.TelegramID,
+			DoeTelegramID:  doe.TelegramID,
+			DillFirstName:  dill.FirstName,
+			DillUsername:   dill.Username,
+			DoeFirstName:   doe.FirstName,
+			DoeUsername:    doe.Username,
+		})
+	}
+
+	return &result, nil
+}
+
+// candidates drops chronically unreliable users and converts the rest for the matcher,
+// with their reliability penalty and blocks applied. Returns the remaining users, their
+// matcher counterparts at the same indices, and the number of excluded users.
+func (m *Matching) candidates(ctx context.Context, users []domain.User) ([]domain.User, []matcher.MatchUser, int, error) {
+	reliability, err := m.reliability.All(ctx)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	blocks, err := m.blocks.GetBlocks(ctx)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("get blocks: %w", err)
+	}
+
+	var (
+		kept       []domain.User
+		matchUsers []matcher.MatchUser
+	)
+	for _, u := range users {
+		rel := reliability[u.TelegramID]
+		if m.reliability.Excluded(rel) {
+			continue
+		}
+
+		matchUsers = append(matchUsers, matcher.MatchUser{
+			Index:      len(kept),
+			Username:   u.Username,
+			Sex:        u.Sex,
+			About:      u.About,
+			TimeRanges: u.TimeRanges,
+			Penalty:    m.reliability.ScorePenalty(rel),
+		})
+		kept = append(kept, u)
+	}
+
+	index := make(map[int64]int, len(kept))
+	for i, u := range kept {
+		index[u.TelegramID] = i
+	}
+	for _, b := range blocks {
+		i, ok := index[b.BlockerID]
+		if !ok {
+			continue
+		}
+		j, ok := index[b.BlockedID]
+		if !ok {
+			continue
+		}
+		if matchUsers[i].Blocked == nil {
+			matchUsers[i].Blocked = make(map[int]bool)
+		}
+		matchUsers[i].Blocked[j] = true
+	}
+
+	return kept, matchUsers, len(users) - len(kept), nil
+}
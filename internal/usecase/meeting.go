@@ -15,9 +15,7 @@ type MeetingNotification struct {
 	MeetingID int64
 	DillID    int64
 	DoeID     int64
-	Place     string
-	Route     string
-	PhotoURL  string
+	Place     *domain.Place
 	Time      time.Time
 }
 
@@ -35,17 +33,29 @@ type MeetResult struct {
 	FullMatches []FullMatchNotification
 }
 
+// RematchSchedule is the outcome of Meeting.ScheduleRematch.
+type RematchSchedule struct {
+	Meetings    []MeetingNotification
+	FullMatches []FullMatchNotification
+	// NotRematched are the stranded participants no meeting can be scheduled for anymore.
+	NotRematched []int64
+}
+
 type Meeting struct {
-	users    domain.UserRepository
-	places   domain.PlaceRepository
-	meetings domain.MeetingRepository
+	users     domain.UserRepository
+	places    domain.PlaceRepository
+	meetings  domain.MeetingRepository
+	proposals domain.ProposalRepository
+	checkIns  domain.CheckInRepository
 }
 
-func NewMeeting(users domain.UserRepository, places domain.PlaceRepository, meetings domain.MeetingRepository) *Meeting {
+func NewMeeting(users domain.UserRepository, places domain.PlaceRepository, meetings domain.MeetingRepository, proposals domain.ProposalRepository, checkIns domain.CheckInRepository) *Meeting {
 	return &Meeting{
-		users:    users,
-		places:   places,
-		meetings: meetings,
+		users:     users,
+		places:    places,
+		meetings:  meetings,
+		proposals: proposals,
+		checkIns:  checkIns,
 	}
 }
 
@@ -70,6 +80,60 @@ func hasEarlySlots(intersection string) bool {
 	return false
 }
 
+// pickPlaceAndTime picks a meeting time from the intersection, not earlier than notBefore,
+// and the best place that is free at that time. If every place is booked,
+// a random one is used. Reports false if no suitable time is found.
+func pickPlaceAndTime(places []domain.Place, bookings []placeBooking, intersection string, notBefore time.Time, loc *time.Location) (*domain.Place, time.Time, bool) {
+	preferred := intersection
+	if len(intersection) == 6 && hasEarlySlots(intersection) {
+		preferred = intersection[:4] + "00"
+	}
+
+	for attempt := 0; attempt < 50; attempt++ {
+		src := preferred
+		if attempt >= 30 {
+			src = intersection
+		}
+		t, err := eventTime(domain.PickRandomTime(src), loc)
+		if err != nil || t.Before(notBefore) {
+			continue
+		}
+
+		for pi := range places {
+			if !isBooked(bookings, places[pi].ID, t) {
+				return &places[pi], t, true
+			}
+		}
+	}
+
+	t, err := eventTime(domain.PickRandomTime(intersection), loc)
+	if err != nil || t.Before(notBefore) {
+		return nil, time.Time{}, false
+	}
+	return &places[rand.Intn(len(places))], t, true
+}
+
+func isBooked(bookings []placeBooking, placeID int64, t time.Time) bool {
+	for _, b := range bookings {
+		if b.placeID != placeID {
+			continue
+		}
+		diff := t.Sub(b.time)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < placeBuffer {
+			return true
+		}
+	}
+	return false
+}
+
+func eventTime(timeStr string, loc *time.Location) (time.Time, error) {
+	full := fmt.Sprintf("%d-02-14 %s", time.Now().Year(), timeStr)
+	return time.ParseInLocation("2006-01-02 15:04", full, loc)
+}
+
 func (m *Meeting) CreateMeetings(ctx context.Context) (*MeetResult, error) {
 	regularMeetings, err := m.meetings.GetRegularMeetings(ctx)
 	if err != nil {
@@ -85,7 +149,7 @@ func (m *Meeting) CreateMeetings(ctx context.Context) (*MeetResult, error) {
 		return nil, ErrNoPairs
 	}
 
-	places, err := m.places.GetAllPlaces(ctx)
+	places, err := m.places.GetActivePlaces(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get places: %w", err)
 	}
@@ -122,59 +186,7 @@ func (m *Meeting) CreateMeetings(ctx context.Context) (*MeetResult, error) {
 
 		intersection := domain.CalculateTimeIntersection(dill.TimeRanges, doe.TimeRanges)
 
-		preferred := intersection
-		if len(intersection) == 6 && hasEarlySlots(intersection) {
-			preferred = intersection[:4] + "00"
-		}
-
-		var assignedPlace *domain.Place
-		var meetingTime time.Time
-		assigned := false
-
-		for attempt := 0; attempt < 50; attempt++ {
-			src := preferred
-			if attempt >= 30 {
-				src = intersection
-			}
-			timeStr := domain.PickRandomTime(src)
-			full := fmt.Sprintf("%d-02-14 %s", time.Now().Year(), timeStr)
-			t, err := time.ParseInLocation("2006-01-02 15:04", full, loc)
-			if err != nil {
-				continue
-			}
-
-			for pi := range places {
-				occupied := false
-				for _, b := range bookings {
-					if b.placeID == places[pi].ID {
-						diff := t.Sub(b.time)
-						if diff < 0 {
-							diff = -diff
-						}
-						if diff < placeBuffer {
-							occupied = true
-							break
-						}
-					}
-				}
-				if !occupied {
-					assignedPlace = &places[pi]
-					meetingTime = t
-					assigned = true
-					break
-				}
-			}
-			if assigned {
-				break
-			}
-		}
-
-		if !assigned {
-			timeStr := domain.PickRandomTime(intersection)
-			full := fmt.Sprintf("%d-02-14 %s", time.Now().Year(), timeStr)
-			meetingTime, _ = time.ParseInLocation("2006-01-02 15:04", full, loc)
-			assignedPlace = &places[rand.Intn(len(places))]
-		}
+		assignedPlace, meetingTime, _ := pickPlaceAndTime(places, bookings, intersection, time.Time{}, loc)
 
 		bookings = append(bookings, placeBooking{placeID: assignedPlace.ID, time: meetingTime})
 
@@ -186,9 +198,7 @@ func (m *Meeting) CreateMeetings(ctx context.Context) (*MeetResult, error) {
 			MeetingID: mt.ID,
 			DillID:    dill.TelegramID,
 			DoeID:     doe.TelegramID,
-			Place:     assignedPlace.Description,
-			Route:     assignedPlace.Route,
-			PhotoURL:  assignedPlace.PhotoURL,
+			Place:     assignedPlace,
 			Time:      meetingTime,
 		})
 	}
@@ -220,6 +230,215 @@ func (m *Meeting) CreateMeetings(ctx context.Context) (*MeetResult, error) {
 	return &result, nil
 }
 
+// ScheduleMeetings assigns a place and time to the given meetings, keeping clear
+// of places already booked by other active meetings and not earlier than notBefore.
+// Meetings that cannot be scheduled are deleted.
+func (m *Meeting) ScheduleMeetings(ctx context.Context, meetingIDs []int64, notBefore time.Time) (*MeetResult, error) {
+	var result MeetResult
+	if len(meetingIDs) == 0 {
+		return &result, nil
+	}
+
+	places, err := m.places.GetActivePlaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get places: %w", err)
+	}
+
+	bookings, err := m.activeBookings(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation("Europe/Samara")
+	if err != nil {
+		return nil, fmt.Errorf("load location: %w", err)
+	}
+
+	for _, id := range meetingIDs {
+		mt, err := m.meetings.GetMeetingByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get meeting: %w", err)
+		}
+		if mt == nil {
+			continue
+		}
+
+		dill, err := m.users.GetUser(ctx, mt.DillID)
+		if err != nil {
+			return nil, fmt.Errorf("get dill: %w", err)
+		}
+		doe, err := m.users.GetUser(ctx, mt.DoeID)
+		if err != nil {
+			return nil, fmt.Errorf("get doe: %w", err)
+		}
+
+		var (
+			place       *domain.Place
+			meetingTime time.Time
+			ok          bool
+		)
+		if dill != nil && doe != nil && len(places) > 0 {
+			intersection := domain.CalculateTimeIntersection(dill.TimeRanges, doe.TimeRanges)
+			place, meetingTime, ok = pickPlaceAndTime(places, bookings, intersection, notBefore, loc)
+		}
+
+		if !ok {
+			if err := m.meetings.DeleteMeeting(ctx, mt.ID); err != nil {
+				return nil, fmt.Errorf("delete meeting: %w", err)
+			}
+			continue
+		}
+
+		bookings = append(bookings, placeBooking{placeID: place.ID, time: meetingTime})
+
+		if err := m.meetings.AssignPlaceAndTime(ctx, mt.ID, place.ID, meetingTime); err != nil {
+			return nil, fmt.Errorf("assign place and time: %w", err)
+		}
+
+		result.Meetings = append(result.Meetings, MeetingNotification{
+			MeetingID: mt.ID,
+			DillID:    dill.TelegramID,
+			DoeID:     doe.TelegramID,
+			Place:     place,
+			Time:      meetingTime,
+		})
+	}
+
+	return &result, nil
+}
+
+// ScheduleRematch picks a place and time for the new regular meetings of the
+// rematch, dropping those that can't get one, and saves them with the full
+// matches. A stranded meeting is marked rematched once its participant is
+// placed, or once no meeting can be scheduled for them anymore; otherwise they
+// are pooled again by the next rematch. Everything is saved in one transaction.
+func (m *Meeting) ScheduleRematch(ctx context.Context, r *RematchResult, notBefore time.Time) (*RematchSchedule, error) {
+	places, err := m.places.GetActivePlaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get places: %w", err)
+	}
+
+	bookings, err := m.activeBookings(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation("Europe/Samara")
+	if err != nil {
+		return nil, fmt.Errorf("load location: %w", err)
+	}
+
+	var (
+		meetings []domain.Meeting
+		placeOf  = make(map[int]*domain.Place)
+		placed   = make(map[int64]bool)
+	)
+	for _, mt := range r.Meetings {
+		if !mt.IsFullmatch {
+			dill, err := m.users.GetUser(ctx, mt.DillID)
+			if err != nil {
+				return nil, fmt.Errorf("get dill: %w", err)
+			}
+			doe, err := m.users.GetUser(ctx, mt.DoeID)
+			if err != nil {
+				return nil, fmt.Errorf("get doe: %w", err)
+			}
+			if dill == nil || doe == nil || len(places) == 0 {
+				continue
+			}
+
+			intersection := domain.CalculateTimeIntersection(dill.TimeRanges, doe.TimeRanges)
+			place, meetingTime, ok := pickPlaceAndTime(places, bookings, intersection, notBefore, loc)
+			if !ok {
+				continue
+			}
+
+			bookings = append(bookings, placeBooking{placeID: place.ID, time: meetingTime})
+			mt.PlaceID, mt.Time = &place.ID, &meetingTime
+			placeOf[len(meetings)] = place
+		}
+
+		meetings = append(meetings, mt)
+		placed[mt.DillID] = true
+		placed[mt.DoeID] = true
+	}
+
+	result := RematchSchedule{FullMatches: r.FullMatches}
+
+	var rematched []int64
+	for _, mt := range r.Stranded {
+		id := mt.Stranded()
+		if id == 0 || placed[id] {
+			rematched = append(rematched, mt.ID)
+			continue
+		}
+
+		user, err := m.users.GetUser(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get stranded: %w", err)
+		}
+		if user == nil || len(places) == 0 || !hasSlotsLeft(user.TimeRanges, notBefore, loc) {
+			rematched = append(rematched, mt.ID)
+			result.NotRematched = append(result.NotRematched, id)
+		}
+	}
+
+	if err := m.meetings.SaveRematch(ctx, meetings, rematched); err != nil {
+		return nil, fmt.Errorf("save rematch: %w", err)
+	}
+
+	for i, mt := range meetings {
+		if mt.IsFullmatch {
+			continue
+		}
+		result.Meetings = append(result.Meetings, MeetingNotification{
+			MeetingID: mt.ID,
+			DillID:    mt.DillID,
+			DoeID:     mt.DoeID,
+			Place:     placeOf[i],
+			Time:      *mt.Time,
+		})
+	}
+	return &result, nil
+}
+
+// hasSlotsLeft reports whether a meeting can still be scheduled within the time
+// ranges: one of their hours is not earlier than notBefore.
+func hasSlotsLeft(timeRanges string, notBefore time.Time, loc *time.Location) bool {
+	for _, h := range domain.AvailableHours(timeRanges) {
+		t, err := eventTime(fmt.Sprintf("%d:00", h), loc)
+		if err == nil && !t.Before(notBefore) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeBookings returns places booked by meetings nobody has dropped out of, except the excluded one.
+func (m *Meeting) activeBookings(ctx context.Context, exclude int64) ([]placeBooking, error) {
+	regularMeetings, err := m.meetings.GetRegularMeetings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get regular meetings: %w", err)
+	}
+
+	var bookings []placeBooking
+	for _, mt := range regularMeetings {
+		if mt.ID == exclude || mt.PlaceID == nil || mt.Time == nil || mt.DillState.Dropped() || mt.DoeState.Dropped() {
+			continue
+		}
+		bookings = append(bookings, placeBooking{placeID: *mt.PlaceID, time: *mt.Time})
+	}
+	return bookings, nil
+}
+
+func (m *Meeting) MarkInvited(ctx context.Context, meetingID int64) error {
+	return m.meetings.MarkInvited(ctx, meetingID)
+}
+
+func (m *Meeting) ExpireUnconfirmed(ctx context.Context, deadline time.Duration) ([]domain.Meeting, error) {
+	return m.meetings.ExpireUnconfirmed(ctx, deadline)
+}
+
 func (m *Meeting) GetMeetingsForInvites(ctx context.Context) (*MeetResult, error) {
 	regularMeetings, err := m.meetings.GetRegularMeetings(ctx)
 	if err != nil {
@@ -238,7 +457,7 @@ func (m *Meeting) GetMeetingsForInvites(ctx context.Context) (*MeetResult, error
 	var result MeetResult
 
 	for _, mt := range regularMeetings {
-		if mt.PlaceID == nil || mt.Time == nil {
+		if mt.PlaceID == nil || mt.Time == nil || mt.DillState.Dropped() || mt.DoeState.Dropped() {
 			continue
 		}
 
@@ -267,9 +486,7 @@ func (m *Meeting) GetMeetingsForInvites(ctx context.Context) (*MeetResult, error
 			MeetingID: mt.ID,
 			DillID:    dill.TelegramID,
 			DoeID:     doe.TelegramID,
-			Place:     place.Description,
-			Route:     place.Route,
-			PhotoURL:  place.PhotoURL,
+			Place:     place,
 			Time:      *mt.Time,
 		})
 	}
@@ -337,24 +554,23 @@ func (m *Meeting) GetUnmatchedUserIDs(ctx context.Context) ([]int64, error) {
 	return unmatched, nil
 }
 
+// ConfirmMeeting confirms the participant's attendance and reports whether both participants
+// have confirmed now. Only the confirmation completing the pair reports true.
 func (m *Meeting) ConfirmMeeting(ctx context.Context, meetingID int64, telegramID int64) (bool, *domain.Meeting, error) {
 	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
 	if err != nil || meeting == nil {
 		return false, nil, err
 	}
 
-	isDill := meeting.DillID == telegramID
-	isDoe := meeting.DoeID == telegramID
-
-	if !isDill && !isDoe {
+	if meeting.DillID != telegramID && meeting.DoeID != telegramID {
 		return false, nil, nil
 	}
 
-	if err := m.meetings.UpdateState(ctx, meetingID, isDill, domain.StateConfirmed); err != nil {
-		return false, nil, err
+	if meeting.DillState.Dropped() || meeting.DoeState.Dropped() {
+		return false, nil, nil
 	}
 
-	updated, err := m.meetings.GetMeetingByID(ctx, meetingID)
+	updated, err := m.transition(ctx, meeting, telegramID, domain.StateConfirmed)
 	if err != nil {
 		return false, nil, err
 	}
@@ -363,29 +579,59 @@ func (m *Meeting) ConfirmMeeting(ctx context.Context, meetingID int64, telegramI
 	return bothConfirmed, updated, nil
 }
 
-func (m *Meeting) CancelMeeting(ctx context.Context, meetingID int64, telegramID int64) (bool, error) {
+// CancelMeeting cancels the participant's attendance. Returns the cancelled meeting, or nil
+// if the user is not its participant, and whether both participants had confirmed it.
+func (m *Meeting) CancelMeeting(ctx context.Context, meetingID int64, telegramID int64) (*domain.Meeting, bool, error) {
 	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
 	if err != nil || meeting == nil {
-		return false, err
+		return nil, false, err
 	}
 
-	dill, err := m.users.GetUser(ctx, meeting.DillID)
-	if err != nil {
-		return false, err
+	if meeting.DillID != telegramID && meeting.DoeID != telegramID {
+		return nil, false, nil
 	}
-	doe, err := m.users.GetUser(ctx, meeting.DoeID)
+
+	wasConfirmed := meeting.Confirmed()
+
+	updated, err := m.transition(ctx, meeting, telegramID, domain.StateCancelled)
 	if err != nil {
-		return false, err
+		return nil, false, err
 	}
+	return updated, wasConfirmed, nil
+}
 
-	if dill != nil && dill.TelegramID == telegramID {
-		return true, m.meetings.UpdateState(ctx, meetingID, true, domain.StateCancelled)
+// transition moves the participant's state, failing with a *domain.TransitionError
+// if the transition is illegal or another one has happened in the meantime.
+func (m *Meeting) transition(ctx context.Context, meeting *domain.Meeting, telegramID int64, to domain.ConfirmationState) (*domain.Meeting, error) {
+	isDill := meeting.DillID == telegramID
+	from := meeting.DoeState
+	if isDill {
+		from = meeting.DillState
 	}
-	if doe != nil && doe.TelegramID == telegramID {
-		return true, m.meetings.UpdateState(ctx, meetingID, false, domain.StateCancelled)
+
+	if err := domain.CheckTransition(from, to); err != nil {
+		return nil, err
 	}
 
-	return false, nil
+	updated, err := m.meetings.TransitionState(ctx, meeting.ID, isDill, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("transition state: %w", err)
+	}
+	if updated != nil {
+		return updated, nil
+	}
+
+	current, err := m.meetings.GetMeetingByID(ctx, meeting.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get meeting: %w", err)
+	}
+	if current != nil {
+		from = current.DoeState
+		if isDill {
+			from = current.DillState
+		}
+	}
+	return nil, &domain.TransitionError{From: from, To: to}
 }
 
 func (m *Meeting) BothConfirmed(ctx context.Context, meetingID int64) (bool, *domain.Meeting, error) {
@@ -485,23 +731,12 @@ func (m *Meeting) SetArrived(ctx context.Context, meetingID int64, telegramID in
 		return err
 	}
 
-	dill, err := m.users.GetUser(ctx, meeting.DillID)
-	if err != nil {
-		return err
-	}
-	doe, err := m.users.GetUser(ctx, meeting.DoeID)
-	if err != nil {
-		return err
-	}
-
-	if dill != nil && dill.TelegramID == telegramID {
-		return m.meetings.UpdateState(ctx, meetingID, true, domain.StateArrived)
-	}
-	if doe != nil && doe.TelegramID == telegramID {
-		return m.meetings.UpdateState(ctx, meetingID, false, domain.StateArrived)
+	if meeting.DillID != telegramID && meeting.DoeID != telegramID {
+		return nil
 	}
 
-	return nil
+	_, err = m.transition(ctx, meeting, telegramID, domain.StateArrived)
+	return err
 }
 
 func (m *Meeting) GetArrivedMeetingID(ctx context.Context, telegramID int64) (int64, error) {
@@ -544,6 +779,35 @@ func (m *Meeting) GetPlace(ctx context.Context, placeID int64) (*domain.Place, e
 	return m.places.GetPlace(ctx, placeID)
 }
 
-func (m *Meeting) GetTelegramIDsForFeedbackRequest(ctx context.Context) ([]int64, error) {
-	return m.meetings.GetTelegramIDsForFeedbackRequest(ctx)
+func (m *Meeting) GetMeetingsForSeeAgain(ctx context.Context, after time.Duration) ([]domain.Meeting, error) {
+	return m.meetings.GetMeetingsForSeeAgain(ctx, after)
+}
+
+func (m *Meeting) MarkSeeAgainAsked(ctx context.Context, meetingID int64) error {
+	return m.meetings.MarkSeeAgainAsked(ctx, meetingID)
+}
+
+// AnswerSeeAgain stores whether the participant wants to meet the partner again.
+// Reports true, along with the partner, only for the answer that makes the wish mutual.
+func (m *Meeting) AnswerSeeAgain(ctx context.Context, meetingID int64, telegramID int64, yes bool) (bool, *domain.User, error) {
+	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil || meeting == nil {
+		return false, nil, err
+	}
+
+	isDill := meeting.DillID == telegramID
+	if !isDill && meeting.DoeID != telegramID {
+		return false, nil, nil
+	}
+
+	_, mutual, err := m.meetings.SetSeeAgain(ctx, meetingID, isDill, yes)
+	if err != nil || !mutual {
+		return false, nil, err
+	}
+
+	partner, err := m.GetPartner(ctx, meetingID, telegramID)
+	if err != nil {
+		return false, nil, err
+	}
+	return partner != nil, partner, nil
 }
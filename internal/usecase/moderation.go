@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var (
+	ErrInvalidReason   = errors.New("invalid report reason")
+	ErrAlreadyReported = errors.New("meeting already reported")
+	ErrReportNotFound  = errors.New("report not found")
+	ErrReportResolved  = errors.New("report already resolved")
+	ErrInvalidStatus   = errors.New("invalid report status")
+	ErrCannotBanAdmin  = errors.New("cannot ban admin")
+	ErrNotBanned       = errors.New("user is not banned")
+)
+
+type Moderation struct {
+	users    domain.UserRepository
+	meetings domain.MeetingRepository
+	reports  domain.ReportRepository
+	blocks   domain.BlockRepository
+}
+
+func NewModeration(users domain.UserRepository, meetings domain.MeetingRepository, reports domain.ReportRepository, blocks domain.BlockRepository) *Moderation {
+	return &Moderation{users: users, meetings: meetings, reports: reports, blocks: blocks}
+}
+
+// Report files a report against the reporter's partner in the meeting into the moderation queue.
+func (m *Moderation) Report(ctx context.Context, meetingID int64, reporterID int64, reason domain.ReportReason) (*domain.Report, error) {
+	if !reason.Valid() {
+		return nil, ErrInvalidReason
+	}
+
+	partnerID, err := m.partner(ctx, meetingID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.Report{
+		MeetingID:  meetingID,
+		ReporterID: reporterID,
+		ReportedID: partnerID,
+		Reason:     reason,
+	}
+	ok, err := m.reports.CreateReport(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("create report: %w", err)
+	}
+	if !ok {
+		return nil, ErrAlreadyReported
+	}
+	return report, nil
+}
+
+// Block makes sure the participant is never matched with their partner in the
+// meeting again and closes the chat between them. Returns the blocked partner.
+func (m *Moderation) Block(ctx context.Context, meetingID int64, blockerID int64) (*domain.User, error) {
+	partnerID, err := m.partner(ctx, meetingID, blockerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.blocks.Block(ctx, blockerID, partnerID); err != nil {
+		return nil, fmt.Errorf("block: %w", err)
+	}
+	return m.users.GetUser(ctx, partnerID)
+}
+
+// NextPending returns the oldest pending report and the number of pending reports,
+// or a nil report if the queue is empty.
+func (m *Moderation) NextPending(ctx context.Context) (*domain.Report, int, error) {
+	report, err := m.reports.GetNextPendingReport(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get next report: %w", err)
+	}
+	if report == nil {
+		return nil, 0, nil
+	}
+
+	pending, err := m.reports.CountPendingReports(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count pending reports: %w", err)
+	}
+	return report, pending, nil
+}
+
+// Resolve closes a pending report with the admin's decision. Banning the reported
+// user also cancels their open meetings; the partners left without a meeting are
+// returned so they can be told, and are picked up by rematching.
+func (m *Moderation) Resolve(ctx context.Context, reportID int64, adminID int64, status domain.ReportStatus) (*domain.Report, []domain.Meeting, error) {
+	if status != domain.ReportStatusDismissed && status != domain.ReportStatusWarned && status != domain.ReportStatusBanned {
+		return nil, nil, ErrInvalidStatus
+	}
+
+	report, err := m.reports.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get report: %w", err)
+	}
+	if report == nil {
+		return nil, nil, ErrReportNotFound
+	}
+
+	if status == domain.ReportStatusBanned {
+		isAdmin, err := m.users.IsAdmin(ctx, report.ReportedID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("check admin: %w", err)
+		}
+		if isAdmin {
+			return nil, nil, ErrCannotBanAdmin
+		}
+	}
+
+	ok, err := m.reports.ResolveReport(ctx, reportID, status, adminID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve report: %w", err)
+	}
+	if !ok {
+		return nil, nil, ErrReportResolved
+	}
+	report.Status = status
+
+	if status != domain.ReportStatusBanned {
+		return report, nil, nil
+	}
+
+	if err := m.users.SetBanned(ctx, report.ReportedID, true); err != nil {
+		return nil, nil, fmt.Errorf("set banned: %w", err)
+	}
+
+	cancelled, err := m.cancelMeetings(ctx, report.ReportedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return report, cancelled, nil
+}
+
+func (m *Moderation) Unban(ctx context.Context, username string) error {
+	user, err := m.users.GetUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	banned, err := m.users.IsBanned(ctx, user.TelegramID)
+	if err != nil {
+		return err
+	}
+	if !banned {
+		return ErrNotBanned
+	}
+	return m.users.SetBanned(ctx, user.TelegramID, false)
+}
+
+func (m *Moderation) IsBanned(ctx context.Context, telegramID int64) (bool, error) {
+	return m.users.IsBanned(ctx, telegramID)
+}
+
+// FormatReport formats the report as a moderation queue card for admins.
+func (m *Moderation) FormatReport(ctx context.Context, report *domain.Report, pending int) (string, error) {
+	reporter, err := m.users.GetUser(ctx, report.ReporterID)
+	if err != nil {
+		return "", fmt.Errorf("get reporter: %w", err)
+	}
+	reported, err := m.users.GetUser(ctx, report.ReportedID)
+	if err != nil {
+		return "", fmt.Errorf("get reported: %w", err)
+	}
+
+	total, err := m.reports.CountReportsAgainst(ctx, report.ReportedID)
+	if err != nil {
+		return "", fmt.Errorf("count reports: %w", err)
+	}
+
+	return messages.Format(messages.M.Moderation.Queue.Card, map[string]string{
+		"id":            fmt.Sprintf("%d", report.ID),
+		"pending":       fmt.Sprintf("%d", pending),
+		"reporter":      mention(report.ReporterID, reporter),
+		"reported":      mention(report.ReportedID, reported),
+		"reason":        messages.M.Moderation.Reasons[string(report.Reason)],
+		"meeting_id":    fmt.Sprintf("%d", report.MeetingID),
+		"created_at":    domain.Timef(report.CreatedAt),
+		"reports_total": fmt.Sprintf("%d", total),
+	}), nil
+}
+
+// FormatAdminNotice formats the alert admins get about a new report.
+func (m *Moderation) FormatAdminNotice(ctx context.Context, report *domain.Report) (string, error) {
+	reporter, err := m.users.GetUser(ctx, report.ReporterID)
+	if err != nil {
+		return "", fmt.Errorf("get reporter: %w", err)
+	}
+	reported, err := m.users.GetUser(ctx, report.ReportedID)
+	if err != nil {
+		return "", fmt.Errorf("get reported: %w", err)
+	}
+
+	return messages.Format(messages.M.Moderation.Report.AdminNotice, map[string]string{
+		"reporter": mention(report.ReporterID, reporter),
+		"reported": mention(report.ReportedID, reported),
+		"reason":   messages.M.Moderation.Reasons[string(report.Reason)],
+	}), nil
+}
+
+// partner returns the other participant of the meeting.
+func (m *Moderation) partner(ctx context.Context, meetingID int64, telegramID int64) (int64, error) {
+	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return 0, fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil {
+		return 0, ErrNotParticipant
+	}
+
+	switch telegramID {
+	case meeting.DillID:
+		return meeting.DoeID, nil
+	case meeting.DoeID:
+		return meeting.DillID, nil
+	}
+	return 0, ErrNotParticipant
+}
+
+// cancelMeetings cancels the user's meetings that have not taken place yet and
+// returns the ones the partner was still in, as they were before the cancellation.
+func (m *Moderation) cancelMeetings(ctx context.Context, telegramID int64) ([]domain.Meeting, error) {
+	meetings, err := m.meetings.GetRegularMeetings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get regular meetings: %w", err)
+	}
+
+	var cancelled []domain.Meeting
+	for _, meeting := range meetings {
+		isDill := meeting.DillID == telegramID
+		if !isDill && meeting.DoeID != telegramID {
+			continue
+		}
+
+		from, partnerState := meeting.DoeState, meeting.DillState
+		if isDill {
+			from, partnerState = meeting.DillState, meeting.DoeState
+		}
+		if !from.CanTransitionTo(domain.StateCancelled) {
+			continue
+		}
+
+		updated, err := m.meetings.TransitionState(ctx, meeting.ID, isDill, from, domain.StateCancelled)
+		if err != nil {
+			return nil, fmt.Errorf("cancel meeting: %w", err)
+		}
+		if updated != nil && !partnerState.Dropped() {
+			cancelled = append(cancelled, meeting)
+		}
+	}
+	return cancelled, nil
+}
+
+func mention(telegramID int64, user *domain.User) string {
+	if user == nil {
+		return fmt.Sprintf("%d", telegramID)
+	}
+	return messages.Mention(user.TelegramID, user.FirstName, user.Username)
+}
@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var (
+	ErrPlaceNotFound   = errors.New("place not found")
+	ErrNoPendingEdit   = errors.New("no pending place edit")
+	ErrInvalidQuality  = errors.New("invalid place quality")
+	ErrInvalidLocation = errors.New("invalid place location")
+	ErrInvalidRadius   = errors.New("invalid place radius")
+	ErrEmptyValue      = errors.New("empty value")
+)
+
+type Places struct {
+	users  domain.UserRepository
+	places domain.PlaceRepository
+	photos domain.PhotoStore
+}
+
+func NewPlaces(users domain.UserRepository, places domain.PlaceRepository, photos domain.PhotoStore) *Places {
+	return &Places{
+		users:  users,
+		places: places,
+		photos: photos,
+	}
+}
+
+func (p *Places) Create(ctx context.Context, description string) (*domain.Place, error) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil, ErrEmptyValue
+	}
+
+	place := &domain.Place{
+		Description: description,
+		Quality:     1,
+		IsActive:    true,
+	}
+	if err := p.places.SavePlace(ctx, place); err != nil {
+		return nil, fmt.Errorf("save place: %w", err)
+	}
+	return place, nil
+}
+
+// CatalogPage returns the place shown on the given page of the catalog along
+// with the normalized page number and the total number of pages.
+func (p *Places) CatalogPage(ctx context.Context, page int) (*domain.Place, int, int, error) {
+	places, err := p.places.GetAllPlaces(ctx)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get all places: %w", err)
+	}
+	if len(places) == 0 {
+		return nil, 0, 0, ErrPlaceNotFound
+	}
+
+	page = ((page % len(places)) + len(places)) % len(places)
+	return &places[page], page, len(places), nil
+}
+
+// CatalogPageOf is like CatalogPage, but looks the page up by place ID.
+func (p *Places) CatalogPageOf(ctx context.Context, placeID int64) (*domain.Place, int, int, error) {
+	places, err := p.places.GetAllPlaces(ctx)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get all places: %w", err)
+	}
+
+	for i := range places {
+		if places[i].ID == placeID {
+			return &places[i], i, len(places), nil
+		}
+	}
+	return nil, 0, 0, ErrPlaceNotFound
+}
+
+func (p *Places) Get(ctx context.Context, placeID int64) (*domain.Place, error) {
+	place, err := p.places.GetPlace(ctx, placeID)
+	if err != nil {
+		return nil, fmt.Errorf("get place: %w", err)
+	}
+	if place == nil {
+		return nil, ErrPlaceNotFound
+	}
+	return place, nil
+}
+
+func (p *Places) ToggleActive(ctx context.Context, placeID int64) (*domain.Place, error) {
+	place, err := p.Get(ctx, placeID)
+	if err != nil {
+		return nil, err
+	}
+
+	place.IsActive = !place.IsActive
+	if err := p.places.SetPlaceActive(ctx, place.ID, place.IsActive); err != nil {
+		return nil, fmt.Errorf("set place active: %w", err)
+	}
+	return place, nil
+}
+
+func (p *Places) StartEdit(ctx context.Context, telegramID int64, placeID int64, field domain.PlaceField) error {
+	if _, err := p.Get(ctx, placeID); err != nil {
+		return err
+	}
+
+	if err := p.places.SetPendingEdit(ctx, telegramID, domain.PlaceEdit{PlaceID: placeID, Field: field}); err != nil {
+		return fmt.Errorf("set pending edit: %w", err)
+	}
+	return p.users.SetUserState(ctx, telegramID, domain.UserStateAwaitingPlaceEdit)
+}
+
+func (p *Places) PendingEdit(ctx context.Context, telegramID int64) (*domain.PlaceEdit, error) {
+	return p.places.GetPendingEdit(ctx, telegramID)
+}
+
+func (p *Places) CancelEdit(ctx context.Context, telegramID int64) error {
+	if err := p.places.ClearPendingEdit(ctx, telegramID); err != nil {
+		return fmt.Errorf("clear pending edit: %w", err)
+	}
+	return p.users.SetUserState(ctx, telegramID, domain.UserStateCompleted)
+}
+
+// ApplyText applies the admin's pending edit of a text field and returns the updated place.
+func (p *Places) ApplyText(ctx context.Context, telegramID int64, text string) (*domain.Place, error) {
+	edit, place, err := p.pending(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrEmptyValue
+	}
+
+	switch edit.Field {
+	case domain.PlaceFieldDescription:
+		place.Description = text
+	case domain.PlaceFieldRoute:
+		place.Route = text
+	case domain.PlaceFieldQuality:
+		quality, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, ErrInvalidQuality
+		}
+		place.Quality = quality
+	case domain.PlaceFieldLocation:
+		lat, lng, err := parseLocation(text)
+		if err != nil {
+			return nil, err
+		}
+		place.Latitude, place.Longitude = &lat, &lng
+	case domain.PlaceFieldRadius:
+		radius, err := strconv.Atoi(text)
+		if err != nil || radius <= 0 {
+			return nil, ErrInvalidRadius
+		}
+		place.Radius = radius
+	default:
+		return nil, ErrNoPendingEdit
+	}
+
+	return place, p.finishEdit(ctx, telegramID, place)
+}
+
+// ApplyPhoto uploads the photo to the photo store under a generated key and attaches it to the place being edited.
+// fileID is the Telegram file_id of the uploaded photo, so it can be resent without re-uploading.
+func (p *Places) ApplyPhoto(ctx context.Context, telegramID int64, r io.Reader, size int64, fileID string) (*domain.Place, error) {
+	edit, place, err := p.pending(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if edit.Field != domain.PlaceFieldPhoto {
+		return nil, ErrNoPendingEdit
+	}
+
+	key := fmt.Sprintf("places/%d-%d.jpg", place.ID, time.Now().UnixNano())
+	if err := p.photos.PutPhoto(ctx, key, r, size); err != nil {
+		return nil, fmt.Errorf("put photo: %w", err)
+	}
+
+	place.PhotoURL = key
+	place.PhotoFileID = fileID
+	return place, p.finishEdit(ctx, telegramID, place)
+}
+
+// ApplyLocation sets the coordinates of the place being edited from a location shared in the chat.
+func (p *Places) ApplyLocation(ctx context.Context, telegramID int64, lat, lng float64) (*domain.Place, error) {
+	edit, place, err := p.pending(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if edit.Field != domain.PlaceFieldLocation {
+		return nil, ErrNoPendingEdit
+	}
+
+	place.Latitude, place.Longitude = &lat, &lng
+	return place, p.finishEdit(ctx, telegramID, place)
+}
+
+// parseLocation parses coordinates written as "latitude, longitude", the way
+// map apps copy them.
+func parseLocation(text string) (float64, float64, error) {
+	parts := strings.Split(text, ",")
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidLocation
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, ErrInvalidLocation
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return 0, 0, ErrInvalidLocation
+	}
+	return lat, lng, nil
+}
+
+func (p *Places) pending(ctx context.Context, telegramID int64) (*domain.PlaceEdit, *domain.Place, error) {
+	edit, err := p.places.GetPendingEdit(ctx, telegramID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get pending edit: %w", err)
+	}
+	if edit == nil {
+		return nil, nil, ErrNoPendingEdit
+	}
+
+	place, err := p.Get(ctx, edit.PlaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return edit, place, nil
+}
+
+func (p *Places) finishEdit(ctx context.Context, telegramID int64, place *domain.Place) error {
+	if err := p.places.UpdatePlace(ctx, place); err != nil {
+		return fmt.Errorf("update place: %w", err)
+	}
+	return p.CancelEdit(ctx, telegramID)
+}
+
+func (p *Places) FormatCard(place *domain.Place) string {
+	status := messages.M.Admin.Places.Active
+	if !place.IsActive {
+		status = messages.M.Admin.Places.Inactive
+	}
+
+	route := place.Route
+	if route == "" {
+		route = "--"
+	}
+
+	coordinates := "--"
+	if place.HasLocation() {
+		coordinates = fmt.Sprintf("%.6f, %.6f", *place.Latitude, *place.Longitude)
+	}
+
+	return messages.Format(messages.M.Admin.Places.Card, map[string]string{
+		"id":          fmt.Sprintf("%d", place.ID),
+		"status":      status,
+		"description": place.Description,
+		"route":       route,
+		"quality":     fmt.Sprintf("%d", place.Quality),
+		"coordinates": coordinates,
+		"radius":      fmt.Sprintf("%d", place.Radius),
+	})
+}
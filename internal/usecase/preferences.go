@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var ErrInvalidCategory = errors.New("invalid notification category")
+
+// Preferences manages which non-urgent notifications users get.
+type Preferences struct {
+	users domain.UserRepository
+}
+
+func NewPreferences(users domain.UserRepository) *Preferences {
+	return &Preferences{users: users}
+}
+
+// Muted returns the notification categories the user has muted.
+func (p *Preferences) Muted(ctx context.Context, telegramID int64) (map[domain.NotificationCategory]bool, error) {
+	categories, err := p.users.GetMutedCategories(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("get muted categories: %w", err)
+	}
+
+	muted := make(map[domain.NotificationCategory]bool, len(categories))
+	for _, c := range categories {
+		muted[c] = true
+	}
+	return muted, nil
+}
+
+// Toggle mutes the category for the user, or unmutes it if it is muted, and
+// returns the muted categories.
+func (p *Preferences) Toggle(ctx context.Context, telegramID int64, category domain.NotificationCategory) (map[domain.NotificationCategory]bool, error) {
+	if !category.Valid() {
+		return nil, ErrInvalidCategory
+	}
+
+	muted, err := p.Muted(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.users.SetMuted(ctx, telegramID, category, !muted[category]); err != nil {
+		return nil, fmt.Errorf("set muted: %w", err)
+	}
+
+	muted[category] = !muted[category]
+	return muted, nil
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var (
+	ErrMeetingUnavailable = errors.New("meeting is not available")
+	ErrProposalResolved   = errors.New("proposal already resolved")
+	ErrInvalidSlot        = errors.New("invalid time slot")
+	ErrNoFreePlace        = errors.New("no free place")
+)
+
+type RescheduleResult struct {
+	Proposal     *domain.Proposal
+	Meeting      MeetingNotification
+	PlaceChanged bool
+
+	// Previous is the meeting as it was before the reschedule.
+	Previous *domain.Meeting
+}
+
+// ProposalSlots returns the times the participant can propose instead of the current one:
+// every hour of the pair's time intersection that is still ahead.
+func (m *Meeting) ProposalSlots(ctx context.Context, meetingID int64, telegramID int64) ([]time.Time, error) {
+	meeting, err := m.activeMeeting(ctx, meetingID, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	dill, err := m.users.GetUser(ctx, meeting.DillID)
+	if err != nil {
+		return nil, fmt.Errorf("get dill: %w", err)
+	}
+	doe, err := m.users.GetUser(ctx, meeting.DoeID)
+	if err != nil {
+		return nil, fmt.Errorf("get doe: %w", err)
+	}
+	if dill == nil || doe == nil {
+		return nil, ErrMeetingUnavailable
+	}
+
+	loc, err := time.LoadLocation("Europe/Samara")
+	if err != nil {
+		return nil, fmt.Errorf("load location: %w", err)
+	}
+
+	intersection := domain.CalculateTimeIntersection(dill.TimeRanges, doe.TimeRanges)
+	now := time.Now()
+
+	var slots []time.Time
+	for _, h := range domain.AvailableHours(intersection) {
+		t, err := eventTime(fmt.Sprintf("%d:00", h), loc)
+		if err != nil || t.Before(now) || (meeting.Time != nil && t.Equal(*meeting.Time)) {
+			continue
+		}
+		slots = append(slots, t)
+	}
+	return slots, nil
+}
+
+// ProposeTime saves the participant's proposal to move the meeting and returns it along with the partner's telegram ID.
+func (m *Meeting) ProposeTime(ctx context.Context, meetingID int64, telegramID int64, t time.Time) (*domain.Proposal, int64, error) {
+	slots, err := m.ProposalSlots(ctx, meetingID, telegramID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	valid := false
+	for _, slot := range slots {
+		if slot.Equal(t) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, 0, ErrInvalidSlot
+	}
+
+	partnerID, err := m.GetPartnerTelegramID(ctx, meetingID, telegramID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get partner: %w", err)
+	}
+
+	proposal := &domain.Proposal{
+		MeetingID:  meetingID,
+		ProposerID: telegramID,
+		Time:       t,
+	}
+	if err := m.proposals.SaveProposal(ctx, proposal); err != nil {
+		return nil, 0, fmt.Errorf("save proposal: %w", err)
+	}
+	return proposal, partnerID, nil
+}
+
+// AcceptProposal moves the meeting to the proposed time, keeping the place if it is still free then,
+// or booking the best free one otherwise. Both participants have to confirm the meeting again.
+//
+// If no place is free, the proposal is declined and ErrNoFreePlace is returned along with the result.
+func (m *Meeting) AcceptProposal(ctx context.Context, proposalID int64, telegramID int64) (*RescheduleResult, error) {
+	proposal, meeting, err := m.pendingProposal(ctx, proposalID, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	result := &RescheduleResult{Proposal: proposal}
+
+	places, err := m.places.GetActivePlaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get places: %w", err)
+	}
+
+	bookings, err := m.activeBookings(ctx, meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var place *domain.Place
+	for i := range places {
+		if isBooked(bookings, places[i].ID, proposal.Time) {
+			continue
+		}
+		if place == nil || (meeting.PlaceID != nil && places[i].ID == *meeting.PlaceID) {
+			place = &places[i]
+		}
+	}
+
+	status := domain.ProposalAccepted
+	if place == nil {
+		status = domain.ProposalDeclined
+	}
+
+	ok, err := m.proposals.ResolveProposal(ctx, proposal.ID, status)
+	if err != nil {
+		return nil, fmt.Errorf("resolve proposal: %w", err)
+	}
+	if !ok {
+		return nil, ErrProposalResolved
+	}
+	if place == nil {
+		return result, ErrNoFreePlace
+	}
+
+	if err := m.meetings.Reschedule(ctx, meeting.ID, place.ID, proposal.Time); err != nil {
+		return nil, fmt.Errorf("reschedule: %w", err)
+	}
+
+	if err := m.proposals.DeclinePendingProposals(ctx, meeting.ID); err != nil {
+		return nil, fmt.Errorf("decline pending proposals: %w", err)
+	}
+
+	result.Previous = meeting
+	result.PlaceChanged = meeting.PlaceID == nil || *meeting.PlaceID != place.ID
+	result.Meeting = MeetingNotification{
+		MeetingID: meeting.ID,
+		DillID:    meeting.DillID,
+		DoeID:     meeting.DoeID,
+		Place:     place,
+		Time:      proposal.Time,
+	}
+	return result, nil
+}
+
+func (m *Meeting) DeclineProposal(ctx context.Context, proposalID int64, telegramID int64) (*domain.Proposal, error) {
+	proposal, _, err := m.pendingProposal(ctx, proposalID, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := m.proposals.ResolveProposal(ctx, proposal.ID, domain.ProposalDeclined)
+	if err != nil {
+		return nil, fmt.Errorf("resolve proposal: %w", err)
+	}
+	if !ok {
+		return nil, ErrProposalResolved
+	}
+	return proposal, nil
+}
+
+// pendingProposal returns the proposal if it still awaits the answer of the given participant.
+func (m *Meeting) pendingProposal(ctx context.Context, proposalID int64, telegramID int64) (*domain.Proposal, *domain.Meeting, error) {
+	proposal, err := m.proposals.GetProposal(ctx, proposalID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get proposal: %w", err)
+	}
+	if proposal == nil || proposal.ProposerID == telegramID {
+		return nil, nil, ErrMeetingUnavailable
+	}
+	if proposal.Status != domain.ProposalPending || proposal.Time.Before(time.Now()) {
+		return nil, nil, ErrProposalResolved
+	}
+
+	meeting, err := m.activeMeeting(ctx, proposal.MeetingID, telegramID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proposal, meeting, nil
+}
+
+// activeMeeting returns the meeting if the user takes part in it and nobody has dropped out.
+func (m *Meeting) activeMeeting(ctx context.Context, meetingID int64, telegramID int64) (*domain.Meeting, error) {
+	meeting, err := m.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil || meeting.IsFullmatch || (meeting.DillID != telegramID && meeting.DoeID != telegramID) {
+		return nil, ErrMeetingUnavailable
+	}
+	if meeting.DillState.Dropped() || meeting.DoeState.Dropped() {
+		return nil, ErrMeetingUnavailable
+	}
+	return meeting, nil
+}
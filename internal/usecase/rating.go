@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var (
+	ErrNotParticipant = errors.New("not a meeting participant")
+	ErrInvalidStars   = errors.New("invalid star rating")
+	ErrInvalidTag     = errors.New("invalid rating tag")
+	ErrNotRated       = errors.New("meeting is not rated yet")
+)
+
+type Rating struct {
+	meetings   domain.MeetingRepository
+	ratings    domain.RatingRepository
+	feedback   domain.FeedbackRepository
+	deliveries domain.DeliveryRepository
+}
+
+func NewRating(meetings domain.MeetingRepository, ratings domain.RatingRepository, feedback domain.FeedbackRepository, deliveries domain.DeliveryRepository) *Rating {
+	return &Rating{meetings: meetings, ratings: ratings, feedback: feedback, deliveries: deliveries}
+}
+
+// MeetingsToRate returns meetings that started at least the given time ago and
+// still have participants to ask for a rating, see domain.Meeting.FeedbackRecipients.
+func (r *Rating) MeetingsToRate(ctx context.Context, after time.Duration) ([]domain.Meeting, error) {
+	return r.meetings.GetMeetingsForFeedbackRequest(ctx, after)
+}
+
+// Request queues the request to rate the meeting, so the participant is asked
+// only once unless sending it fails. Reports false if they already were.
+func (r *Rating) Request(ctx context.Context, meetingID int64, telegramID int64, msg domain.OutboxMessage) (bool, error) {
+	return r.deliveries.Deliver(ctx, domain.DeliveryFeedbackRequest, meetingID, telegramID, []domain.OutboxMessage{msg})
+}
+
+// Rate stores the participant's star rating for the meeting, replacing a previous one.
+func (r *Rating) Rate(ctx context.Context, meetingID int64, telegramID int64, stars int) (*domain.Rating, error) {
+	if stars < 1 || stars > 5 {
+		return nil, ErrInvalidStars
+	}
+	if err := r.checkParticipant(ctx, meetingID, telegramID); err != nil {
+		return nil, err
+	}
+
+	if err := r.ratings.SaveStars(ctx, meetingID, telegramID, stars); err != nil {
+		return nil, fmt.Errorf("save stars: %w", err)
+	}
+	return r.ratings.GetRating(ctx, meetingID, telegramID)
+}
+
+// ToggleTag adds the tag to the participant's rating, or removes it if already set.
+func (r *Rating) ToggleTag(ctx context.Context, meetingID int64, telegramID int64, tag domain.RatingTag) (*domain.Rating, error) {
+	if !tag.Valid() {
+		return nil, ErrInvalidTag
+	}
+
+	rating, err := r.ratings.GetRating(ctx, meetingID, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("get rating: %w", err)
+	}
+	if rating == nil {
+		return nil, ErrNotRated
+	}
+
+	tags := make([]domain.RatingTag, 0, len(rating.Tags)+1)
+	for _, t := range rating.Tags {
+		if t != tag {
+			tags = append(tags, t)
+		}
+	}
+	if !rating.HasTag(tag) {
+		tags = append(tags, tag)
+	}
+
+	if err := r.ratings.SetTags(ctx, meetingID, telegramID, tags); err != nil {
+		return nil, fmt.Errorf("set tags: %w", err)
+	}
+	rating.Tags = tags
+	return rating, nil
+}
+
+// Comment attaches free text to the participant's latest rating. Participants
+// who have not rated any meeting leave it as general feedback instead.
+func (r *Rating) Comment(ctx context.Context, telegramID int64, text string) error {
+	ok, err := r.ratings.SetLatestComment(ctx, telegramID, text)
+	if err != nil {
+		return fmt.Errorf("set comment: %w", err)
+	}
+	if ok {
+		return nil
+	}
+	return r.feedback.Save(ctx, telegramID, text)
+}
+
+func (r *Rating) checkParticipant(ctx context.Context, meetingID int64, telegramID int64) error {
+	meeting, err := r.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil || (meeting.DillID != telegramID && meeting.DoeID != telegramID) {
+		return ErrNotParticipant
+	}
+	return nil
+}
@@ -2,16 +2,46 @@ package usecase
 
 import (
 	"context"
-
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
 	"github.com/jus1d/kypidbot/internal/domain"
 )
 
+var (
+	ErrAboutTooShort = errors.New("about is too short")
+	ErrAboutTooLong  = errors.New("about is too long")
+	ErrAboutNoText   = errors.New("about has only mentions and links")
+)
+
+// mentionsAndLinks matches @usernames and links, which don't tell anything
+// about the user by themselves.
+var mentionsAndLinks = regexp.MustCompile(`(?i)@\w+|\S+://\S+|(www\.|t\.me/)\S+`)
+
 type Registration struct {
-	users domain.UserRepository
+	users               domain.UserRepository
+	aboutMinLength      int
+	aboutMaxLength      int
+	aboutFollowUpLength int
 }
 
-func NewRegistration(users domain.UserRepository) *Registration {
-	return &Registration{users: users}
+// NewRegistration creates the registration usecase. About texts must be from
+// aboutMinLength to aboutMaxLength characters long, and ones shorter than
+// aboutFollowUpLength are followed up with a question.
+func NewRegistration(users domain.UserRepository, aboutMinLength, aboutMaxLength, aboutFollowUpLength int) *Registration {
+	return &Registration{
+		users:               users,
+		aboutMinLength:      aboutMinLength,
+		aboutMaxLength:      aboutMaxLength,
+		aboutFollowUpLength: aboutFollowUpLength,
+	}
 }
 
 func (r *Registration) SaveUser(ctx context.Context, u *domain.User) error {
@@ -30,6 +60,18 @@ func (r *Registration) GetState(ctx context.Context, telegramID int64) (domain.U
 	return r.users.GetUserState(ctx, telegramID)
 }
 
+// BeginEdit starts editing a single profile step, after which the user
+// returns to the profile instead of going on with registration.
+func (r *Registration) BeginEdit(ctx context.Context, telegramID int64, state domain.UserState) error {
+	return r.users.BeginEdit(ctx, telegramID, state)
+}
+
+// FinishEdit ends editing a single profile step and reports false if the user
+// is going through registration instead.
+func (r *Registration) FinishEdit(ctx context.Context, telegramID int64) (bool, error) {
+	return r.users.FinishEdit(ctx, telegramID)
+}
+
 func (r *Registration) SetSex(ctx context.Context, telegramID int64, sex string) error {
 	return r.users.SetUserSex(ctx, telegramID, sex)
 }
@@ -38,6 +80,67 @@ func (r *Registration) SetAbout(ctx context.Context, telegramID int64, about str
 	return r.users.SetUserAbout(ctx, telegramID, about)
 }
 
+// CheckAbout validates the about text and reports whether it is too short to
+// match the user well, so they should be asked a follow-up question.
+func (r *Registration) CheckAbout(about string) (bool, error) {
+	about = strings.TrimSpace(about)
+	length := utf8.RuneCountInString(about)
+
+	switch {
+	case length > r.aboutMaxLength:
+		return false, ErrAboutTooLong
+	case !strings.ContainsFunc(mentionsAndLinks.ReplaceAllString(about, ""), unicode.IsLetter):
+		return false, ErrAboutNoText
+	case length < r.aboutMinLength:
+		return false, ErrAboutTooShort
+	}
+
+	return length < r.aboutFollowUpLength, nil
+}
+
+// FormatAboutError formats the reason the about text was rejected by CheckAbout.
+func (r *Registration) FormatAboutError(err error, about string) string {
+	switch {
+	case errors.Is(err, ErrAboutTooLong):
+		return messages.Format(messages.M.Profile.About.TooLong, map[string]string{
+			"max":    fmt.Sprintf("%d", r.aboutMaxLength),
+			"length": fmt.Sprintf("%d", utf8.RuneCountInString(strings.TrimSpace(about))),
+		})
+	case errors.Is(err, ErrAboutNoText):
+		return messages.M.Profile.About.NoText
+	default:
+		return messages.Format(messages.M.Profile.About.TooShort, map[string]string{
+			"min": fmt.Sprintf("%d", r.aboutMinLength),
+		})
+	}
+}
+
+// FollowUpQuestion returns a question that helps the user tell more about themselves:
+// the first one about a topic the about text does not mention yet.
+func (r *Registration) FollowUpQuestion(about string) string {
+	about = strings.ToLower(about)
+
+	followUps := messages.M.Profile.About.FollowUps
+	for _, f := range followUps {
+		mentioned := slices.ContainsFunc(f.Keywords, func(k string) bool {
+			return strings.Contains(about, strings.ToLower(k))
+		})
+		if !mentioned {
+			return f.Question
+		}
+	}
+	return followUps[len(followUps)-1].Question
+}
+
+// AskAboutDetails keeps the short about text and waits for the answer to the
+// follow-up question, staying in the registration or profile edit.
+func (r *Registration) AskAboutDetails(ctx context.Context, telegramID int64, about string) error {
+	if err := r.users.SetUserAbout(ctx, telegramID, strings.TrimSpace(about)); err != nil {
+		return fmt.Errorf("set about: %w", err)
+	}
+	return r.users.SetStep(ctx, telegramID, domain.UserStateAwaitingAboutDetails)
+}
+
 func (r *Registration) GetTimeRanges(ctx context.Context, telegramID int64) (string, error) {
 	return r.users.GetTimeRanges(ctx, telegramID)
 }
@@ -65,3 +168,42 @@ func (r *Registration) GetReferralLeaderboard(ctx context.Context) ([]domain.Ref
 func (r *Registration) GetUnregisteredUsers(ctx context.Context) ([]domain.User, error) {
 	return r.users.GetUnregisteredUsers(ctx)
 }
+
+// FormatProfile formats the user's answers and participation status.
+func (r *Registration) FormatProfile(u *domain.User) string {
+	m := messages.M.Profile.View
+
+	sex := m.NotSet
+	switch u.Sex {
+	case "male":
+		sex = messages.M.UI.Buttons.Sex.Male
+	case "female":
+		sex = messages.M.UI.Buttons.Sex.Female
+	}
+
+	about := m.NotSet
+	if u.About != "" {
+		about = html.EscapeString(u.About)
+	}
+
+	ranges := domain.MergeSelectedRanges(domain.BinaryToSet(u.TimeRanges))
+	timeRanges := m.NotSet
+	if len(ranges) > 0 {
+		timeRanges = "- " + strings.Join(ranges, "\n- ")
+	}
+
+	status := m.Statuses.NotRegistered
+	switch {
+	case u.IsRegistered && u.OptedOut:
+		status = m.Statuses.OptedOut
+	case u.IsRegistered:
+		status = m.Statuses.Participating
+	}
+
+	return messages.Format(m.Card, map[string]string{
+		"sex":         sex,
+		"about":       about,
+		"time_ranges": timeRanges,
+		"status":      status,
+	})
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var (
+	ErrRelayClosed = errors.New("relay closed")
+	ErrRateLimited = errors.New("rate limited")
+)
+
+const relayLogLimit = 50
+
+type Relay struct {
+	users      domain.UserRepository
+	meetings   domain.MeetingRepository
+	relays     domain.RelayRepository
+	blocks     domain.BlockRepository
+	rateLimit  int
+	rateWindow time.Duration
+	closeAfter time.Duration
+}
+
+// NewRelay creates the relay usecase. At most rateLimit messages per meeting are relayed
+// within rateWindow, and the chat closes closeAfter the meeting starts or once either
+// participant blocks the other.
+func NewRelay(users domain.UserRepository, meetings domain.MeetingRepository, relays domain.RelayRepository, blocks domain.BlockRepository, rateLimit int, rateWindow time.Duration, closeAfter time.Duration) *Relay {
+	return &Relay{
+		users:      users,
+		meetings:   meetings,
+		relays:     relays,
+		blocks:     blocks,
+		rateLimit:  rateLimit,
+		rateWindow: rateWindow,
+		closeAfter: closeAfter,
+	}
+}
+
+// Start puts the user into the chat with their partner in the given meeting.
+func (r *Relay) Start(ctx context.Context, meetingID int64, telegramID int64) error {
+	if _, err := r.openMeeting(ctx, meetingID, telegramID); err != nil {
+		return err
+	}
+
+	if err := r.relays.StartSession(ctx, telegramID, meetingID); err != nil {
+		return fmt.Errorf("start session: %w", err)
+	}
+	return r.users.SetUserState(ctx, telegramID, domain.UserStateChatting)
+}
+
+func (r *Relay) Leave(ctx context.Context, telegramID int64) error {
+	if err := r.relays.EndSession(ctx, telegramID); err != nil {
+		return fmt.Errorf("end session: %w", err)
+	}
+	return r.users.SetUserState(ctx, telegramID, domain.UserStateCompleted)
+}
+
+// Send records the user's message to their partner and returns it for delivery.
+// If the chat can no longer be used, the user leaves it and ErrRelayClosed is returned.
+func (r *Relay) Send(ctx context.Context, telegramID int64, text string) (*domain.RelayMessage, error) {
+	meetingID, err := r.relays.GetSession(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	var meeting *domain.Meeting
+	if meetingID != 0 {
+		meeting, err = r.openMeeting(ctx, meetingID, telegramID)
+		if err != nil && !errors.Is(err, ErrRelayClosed) {
+			return nil, err
+		}
+	}
+	if meeting == nil {
+		if err := r.Leave(ctx, telegramID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRelayClosed
+	}
+
+	count, err := r.relays.CountMessagesSince(ctx, meetingID, time.Now().Add(-r.rateWindow))
+	if err != nil {
+		return nil, fmt.Errorf("count messages: %w", err)
+	}
+	if count >= r.rateLimit {
+		return nil, ErrRateLimited
+	}
+
+	recipientID := meeting.DoeID
+	if meeting.DoeID == telegramID {
+		recipientID = meeting.DillID
+	}
+
+	msg := &domain.RelayMessage{
+		MeetingID:   meetingID,
+		SenderID:    telegramID,
+		RecipientID: recipientID,
+		Text:        text,
+	}
+	if err := r.relays.SaveMessage(ctx, msg); err != nil {
+		return nil, fmt.Errorf("save message: %w", err)
+	}
+	return msg, nil
+}
+
+// FormatMeetingLog formats the latest relayed messages of the meeting for admins.
+func (r *Relay) FormatMeetingLog(ctx context.Context, meetingID int64) (string, error) {
+	list, err := r.relays.GetMessages(ctx, meetingID, relayLogLimit)
+	if err != nil {
+		return "", fmt.Errorf("get messages: %w", err)
+	}
+	return r.formatLog(ctx, list), nil
+}
+
+// FormatUserLog formats the latest relayed messages sent or received by the user for admins.
+func (r *Relay) FormatUserLog(ctx context.Context, username string) (string, error) {
+	user, err := r.users.GetUserByUsername(ctx, username)
+	if err != nil {
+		return "", fmt.Errorf("get user: %w", err)
+	}
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+
+	list, err := r.relays.GetUserMessages(ctx, user.TelegramID, relayLogLimit)
+	if err != nil {
+		return "", fmt.Errorf("get user messages: %w", err)
+	}
+	return r.formatLog(ctx, list), nil
+}
+
+func (r *Relay) formatLog(ctx context.Context, list []domain.RelayMessage) string {
+	if len(list) == 0 {
+		return messages.M.Relay.Log.Empty
+	}
+
+	mentions := make(map[int64]string)
+	var sb strings.Builder
+	sb.WriteString(messages.M.Relay.Log.Title)
+
+	for _, m := range list {
+		mention, ok := mentions[m.SenderID]
+		if !ok {
+			mention = fmt.Sprintf("%d", m.SenderID)
+			if u, _ := r.users.GetUser(ctx, m.SenderID); u != nil {
+				mention = messages.Mention(u.TelegramID, u.FirstName, u.Username)
+			}
+			mentions[m.SenderID] = mention
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(messages.Format(messages.M.Relay.Log.Entry, map[string]string{
+			"meeting_id": fmt.Sprintf("%d", m.MeetingID),
+			"time":       domain.Timef(m.CreatedAt),
+			"sender":     mention,
+			"text":       html.EscapeString(m.Text),
+		}))
+	}
+
+	return sb.String()
+}
+
+// openMeeting returns the meeting if the user takes part in it and the partners can still chat.
+func (r *Relay) openMeeting(ctx context.Context, meetingID int64, telegramID int64) (*domain.Meeting, error) {
+	meeting, err := r.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil || meeting.IsFullmatch || (meeting.DillID != telegramID && meeting.DoeID != telegramID) {
+		return nil, ErrRelayClosed
+	}
+	if meeting.DillState.Dropped() || meeting.DoeState.Dropped() {
+		return nil, ErrRelayClosed
+	}
+	if meeting.Time != nil && time.Now().After(meeting.Time.Add(r.closeAfter)) {
+		return nil, ErrRelayClosed
+	}
+
+	blocked, err := r.blocks.IsBlocked(ctx, meeting.DillID, meeting.DoeID)
+	if err != nil {
+		return nil, fmt.Errorf("check block: %w", err)
+	}
+	if blocked {
+		return nil, ErrRelayClosed
+	}
+	return meeting, nil
+}
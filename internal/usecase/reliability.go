@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+type Reliability struct {
+	reliability      domain.ReliabilityRepository
+	noShowAfter      time.Duration
+	lateCancelWithin time.Duration
+	penaltyWeight    float64
+	excludePenalty   int
+}
+
+// NewReliability creates the reliability usecase. A confirmed meeting counts as a no-show
+// noShowAfter it started, and a cancellation is late if made less than lateCancelWithin
+// before the meeting. Each penalty point lowers the user's pair scores by penaltyWeight,
+// and users with a penalty of at least excludePenalty are not matched at all.
+func NewReliability(reliability domain.ReliabilityRepository, noShowAfter, lateCancelWithin time.Duration, penaltyWeight float64, excludePenalty int) *Reliability {
+	return &Reliability{
+		reliability:      reliability,
+		noShowAfter:      noShowAfter,
+		lateCancelWithin: lateCancelWithin,
+		penaltyWeight:    penaltyWeight,
+		excludePenalty:   excludePenalty,
+	}
+}
+
+func (r *Reliability) Get(ctx context.Context, telegramID int64) (domain.Reliability, error) {
+	rel, err := r.reliability.GetReliability(ctx, telegramID, r.noShowAfter, r.lateCancelWithin)
+	if err != nil {
+		return domain.Reliability{}, fmt.Errorf("get reliability: %w", err)
+	}
+	return rel, nil
+}
+
+func (r *Reliability) All(ctx context.Context) (map[int64]domain.Reliability, error) {
+	all, err := r.reliability.GetAllReliability(ctx, r.noShowAfter, r.lateCancelWithin)
+	if err != nil {
+		return nil, fmt.Errorf("get all reliability: %w", err)
+	}
+	return all, nil
+}
+
+func (r *Reliability) Excluded(rel domain.Reliability) bool {
+	return r.excludePenalty > 0 && rel.Penalty() >= r.excludePenalty
+}
+
+// ScorePenalty is how much the user's pair scores are lowered in matching.
+func (r *Reliability) ScorePenalty(rel domain.Reliability) float64 {
+	return r.penaltyWeight * float64(rel.Penalty())
+}
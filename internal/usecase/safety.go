@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jus1d/kypidbot/internal/config/messages"
+	"github.com/jus1d/kypidbot/internal/domain"
+)
+
+var ErrIncidentNotFound = errors.New("incident not found")
+
+type Safety struct {
+	users           domain.UserRepository
+	meetings        domain.MeetingRepository
+	places          domain.PlaceRepository
+	incidents       domain.IncidentRepository
+	organizerChatID int64
+}
+
+// NewSafety creates the safety usecase. Calls for help go to organizerChatID,
+// or to every admin if it is 0.
+func NewSafety(users domain.UserRepository, meetings domain.MeetingRepository, places domain.PlaceRepository, incidents domain.IncidentRepository, organizerChatID int64) *Safety {
+	return &Safety{
+		users:           users,
+		meetings:        meetings,
+		places:          places,
+		incidents:       incidents,
+		organizerChatID: organizerChatID,
+	}
+}
+
+// Alert logs the participant's call for help in the meeting as an incident.
+func (s *Safety) Alert(ctx context.Context, meetingID int64, telegramID int64) (*domain.Incident, error) {
+	meeting, err := s.meetings.GetMeetingByID(ctx, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil || (meeting.DillID != telegramID && meeting.DoeID != telegramID) {
+		return nil, ErrNotParticipant
+	}
+
+	incident := &domain.Incident{MeetingID: meetingID, TelegramID: telegramID}
+	if err := s.incidents.CreateIncident(ctx, incident); err != nil {
+		return nil, fmt.Errorf("create incident: %w", err)
+	}
+	return incident, nil
+}
+
+// Acknowledge records that the organizer took the incident. Reports false if
+// someone already has.
+func (s *Safety) Acknowledge(ctx context.Context, incidentID int64, organizerID int64) (*domain.Incident, bool, error) {
+	ok, err := s.incidents.AcknowledgeIncident(ctx, incidentID, organizerID)
+	if err != nil {
+		return nil, false, fmt.Errorf("acknowledge incident: %w", err)
+	}
+
+	incident, err := s.incidents.GetIncident(ctx, incidentID)
+	if err != nil {
+		return nil, false, fmt.Errorf("get incident: %w", err)
+	}
+	if incident == nil {
+		return nil, false, ErrIncidentNotFound
+	}
+	return incident, ok, nil
+}
+
+// AlertChats returns the chats a call for help is sent to.
+func (s *Safety) AlertChats(ctx context.Context) ([]int64, error) {
+	if s.organizerChatID != 0 {
+		return []int64{s.organizerChatID}, nil
+	}
+
+	admins, err := s.users.GetAdmins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get admins: %w", err)
+	}
+
+	ids := make([]int64, 0, len(admins))
+	for _, a := range admins {
+		ids = append(ids, a.TelegramID)
+	}
+	return ids, nil
+}
+
+// FormatAlert formats the incident for organizers with everything needed to
+// find the participants: the meeting, its place and time, and both participants.
+func (s *Safety) FormatAlert(ctx context.Context, incident *domain.Incident) (string, error) {
+	meeting, err := s.meetings.GetMeetingByID(ctx, incident.MeetingID)
+	if err != nil {
+		return "", fmt.Errorf("get meeting: %w", err)
+	}
+	if meeting == nil {
+		return "", ErrNotParticipant
+	}
+
+	partnerID := meeting.DoeID
+	if meeting.DoeID == incident.TelegramID {
+		partnerID = meeting.DillID
+	}
+
+	user, err := s.users.GetUser(ctx, incident.TelegramID)
+	if err != nil {
+		return "", fmt.Errorf("get user: %w", err)
+	}
+	partner, err := s.users.GetUser(ctx, partnerID)
+	if err != nil {
+		return "", fmt.Errorf("get partner: %w", err)
+	}
+
+	place, when := "—", "—"
+	if meeting.PlaceID != nil {
+		p, err := s.places.GetPlace(ctx, *meeting.PlaceID)
+		if err != nil {
+			return "", fmt.Errorf("get place: %w", err)
+		}
+		if p != nil {
+			place = p.Description
+		}
+	}
+	if meeting.Time != nil {
+		when = domain.Timef(*meeting.Time)
+	}
+
+	content := messages.Format(messages.M.Safety.Alert, map[string]string{
+		"id":         fmt.Sprintf("%d", incident.ID),
+		"meeting_id": fmt.Sprintf("%d", meeting.ID),
+		"place":      place,
+		"time":       when,
+		"user":       mention(incident.TelegramID, user),
+		"partner":    mention(partnerID, partner),
+	})
+
+	if incident.AcknowledgedBy != nil {
+		organizer, err := s.users.GetUser(ctx, *incident.AcknowledgedBy)
+		if err != nil {
+			return "", fmt.Errorf("get organizer: %w", err)
+		}
+		content += "\n\n" + messages.Format(messages.M.Safety.Acknowledged, map[string]string{
+			"organizer": mention(*incident.AcknowledgedBy, organizer),
+		})
+	}
+	return content, nil
+}
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TYPE user_state ADD VALUE IF NOT EXISTS 'awaiting_place_edit';
+
+ALTER TABLE places ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE place_edits (
+    telegram_id BIGINT PRIMARY KEY REFERENCES users(telegram_id),
+    place_id INTEGER NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    field TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS place_edits;
+ALTER TABLE places DROP COLUMN is_active;
+-- Note: cannot remove enum value in PostgreSQL
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE places ADD COLUMN photo_file_id TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE places DROP COLUMN photo_file_id;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TYPE confirmation_state ADD VALUE IF NOT EXISTS 'expired';
+ALTER TABLE meetings ADD COLUMN invited_at TIMESTAMPTZ;
+ALTER TABLE meetings ADD COLUMN rematched BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE meetings DROP COLUMN rematched;
+ALTER TABLE meetings DROP COLUMN invited_at;
+-- Note: cannot remove enum value in PostgreSQL
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TYPE proposal_status AS ENUM ('pending', 'accepted', 'declined');
+
+CREATE TABLE meeting_proposals (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    proposer_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    time TIMESTAMPTZ NOT NULL,
+    status proposal_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS meeting_proposals;
+DROP TYPE IF EXISTS proposal_status;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE meetings ADD COLUMN see_again_asked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meetings ADD COLUMN dill_see_again BOOLEAN;
+ALTER TABLE meetings ADD COLUMN doe_see_again BOOLEAN;
+
+-- +goose Down
+ALTER TABLE meetings DROP COLUMN doe_see_again;
+ALTER TABLE meetings DROP COLUMN dill_see_again;
+ALTER TABLE meetings DROP COLUMN see_again_asked;
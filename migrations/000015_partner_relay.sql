@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TYPE user_state ADD VALUE IF NOT EXISTS 'chatting';
+
+CREATE TABLE relay_sessions (
+    telegram_id BIGINT PRIMARY KEY REFERENCES users(telegram_id),
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE relay_messages (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    sender_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    recipient_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX relay_messages_meeting_id_created_at_idx ON relay_messages (meeting_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS relay_messages;
+DROP TABLE IF EXISTS relay_sessions;
+-- Note: cannot remove enum value in PostgreSQL
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TYPE user_state ADD VALUE IF NOT EXISTS 'awaiting_location';
+
+ALTER TABLE places ADD COLUMN latitude DOUBLE PRECISION;
+ALTER TABLE places ADD COLUMN longitude DOUBLE PRECISION;
+ALTER TABLE places ADD COLUMN radius INTEGER NOT NULL DEFAULT 150;
+
+CREATE TABLE check_ins (
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    distance DOUBLE PRECISION,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (meeting_id, telegram_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS check_ins;
+ALTER TABLE places DROP COLUMN radius;
+ALTER TABLE places DROP COLUMN longitude;
+ALTER TABLE places DROP COLUMN latitude;
+-- Note: cannot remove enum value in PostgreSQL
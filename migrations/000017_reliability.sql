@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE meetings ADD COLUMN dill_cancelled_at TIMESTAMPTZ;
+ALTER TABLE meetings ADD COLUMN doe_cancelled_at TIMESTAMPTZ;
+
+CREATE TABLE meetings_archive (
+    id INTEGER PRIMARY KEY,
+    dill_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    doe_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    time TIMESTAMPTZ,
+    dill_state confirmation_state NOT NULL,
+    doe_state confirmation_state NOT NULL,
+    dill_cant_find BOOLEAN NOT NULL DEFAULT FALSE,
+    doe_cant_find BOOLEAN NOT NULL DEFAULT FALSE,
+    dill_cancelled_at TIMESTAMPTZ,
+    doe_cancelled_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX meetings_archive_dill_id_idx ON meetings_archive (dill_id);
+CREATE INDEX meetings_archive_doe_id_idx ON meetings_archive (doe_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS meetings_archive;
+ALTER TABLE meetings DROP COLUMN doe_cancelled_at;
+ALTER TABLE meetings DROP COLUMN dill_cancelled_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE meeting_reminders (
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    before_seconds INTEGER NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (meeting_id, telegram_id, before_seconds)
+);
+
+ALTER TABLE meetings DROP COLUMN users_notified;
+
+-- +goose Down
+ALTER TABLE meetings ADD COLUMN users_notified BOOLEAN NOT NULL DEFAULT FALSE;
+DROP TABLE IF EXISTS meeting_reminders;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE meeting_ratings (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL,
+    rater_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    stars SMALLINT NOT NULL CHECK (stars BETWEEN 1 AND 5),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (meeting_id, rater_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS meeting_ratings;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE meetings ADD COLUMN dill_feedback_requested BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meetings ADD COLUMN doe_feedback_requested BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE meetings DROP COLUMN doe_feedback_requested;
+ALTER TABLE meetings DROP COLUMN dill_feedback_requested;
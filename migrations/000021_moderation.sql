@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TYPE report_reason AS ENUM ('harassment', 'offensive', 'fake_profile', 'no_show', 'other');
+CREATE TYPE report_status AS ENUM ('pending', 'dismissed', 'warned', 'banned');
+
+CREATE TABLE reports (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL,
+    reporter_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    reported_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    reason report_reason NOT NULL,
+    status report_status NOT NULL DEFAULT 'pending',
+    resolved_by BIGINT REFERENCES users(telegram_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    UNIQUE (meeting_id, reporter_id)
+);
+
+CREATE INDEX reports_status_idx ON reports (status);
+CREATE INDEX reports_reported_id_idx ON reports (reported_id);
+
+CREATE TABLE blocks (
+    blocker_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    blocked_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+ALTER TABLE users ADD COLUMN banned BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN banned;
+DROP TABLE IF EXISTS blocks;
+DROP TABLE IF EXISTS reports;
+DROP TYPE IF EXISTS report_status;
+DROP TYPE IF EXISTS report_reason;
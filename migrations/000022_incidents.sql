@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE incidents (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL,
+    telegram_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    acknowledged_by BIGINT,
+    acknowledged_at TIMESTAMPTZ
+);
+
+CREATE INDEX incidents_meeting_id_idx ON incidents (meeting_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS incidents;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TYPE outbox_kind AS ENUM ('text', 'place', 'venue');
+CREATE TYPE outbox_status AS ENUM ('pending', 'sent', 'failed');
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    kind outbox_kind NOT NULL DEFAULT 'text',
+    text TEXT NOT NULL DEFAULT '',
+    markup JSONB,
+    place_id INTEGER,
+    meeting_id INTEGER,
+    message_key TEXT,
+    status outbox_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    message_id INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at, id) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
+DROP TYPE IF EXISTS outbox_status;
+DROP TYPE IF EXISTS outbox_kind;
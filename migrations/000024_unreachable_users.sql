@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN unreachable BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN unreachable;
@@ -0,0 +1,38 @@
+-- +goose Up
+ALTER TYPE user_state ADD VALUE IF NOT EXISTS 'awaiting_broadcast';
+ALTER TYPE user_state ADD VALUE IF NOT EXISTS 'awaiting_broadcast_time';
+ALTER TYPE outbox_kind ADD VALUE IF NOT EXISTS 'copy';
+
+CREATE TYPE broadcast_status AS ENUM ('draft', 'scheduled', 'sending', 'done', 'cancelled');
+
+CREATE TABLE broadcasts (
+    id SERIAL PRIMARY KEY,
+    author_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    status broadcast_status NOT NULL DEFAULT 'draft',
+    segment TEXT NOT NULL DEFAULT '',
+    source_chat_id BIGINT,
+    source_message_id INTEGER,
+    scheduled_at TIMESTAMPTZ,
+    total INTEGER NOT NULL DEFAULT 0,
+    progress_message_id INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX broadcasts_status_idx ON broadcasts (status);
+
+ALTER TABLE outbox ADD COLUMN from_chat_id BIGINT;
+ALTER TABLE outbox ADD COLUMN from_message_id INTEGER;
+ALTER TABLE outbox ADD COLUMN broadcast_id INTEGER REFERENCES broadcasts(id);
+
+CREATE INDEX outbox_broadcast_idx ON outbox (broadcast_id) WHERE broadcast_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS outbox_broadcast_idx;
+ALTER TABLE outbox DROP COLUMN broadcast_id;
+ALTER TABLE outbox DROP COLUMN from_message_id;
+ALTER TABLE outbox DROP COLUMN from_chat_id;
+DROP TABLE IF EXISTS broadcasts;
+DROP TYPE IF EXISTS broadcast_status;
+-- Note: cannot remove enum value in PostgreSQL
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE jobs (
+    name TEXT PRIMARY KEY,
+    schedule TEXT NOT NULL DEFAULT '',
+    last_slot_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    last_finished_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,56 @@
+-- +goose Up
+CREATE TYPE delivery_status AS ENUM ('claimed', 'sent', 'failed');
+
+CREATE TABLE deliveries (
+    kind TEXT NOT NULL,
+    subject BIGINT NOT NULL,
+    recipient BIGINT NOT NULL REFERENCES users(telegram_id),
+    status delivery_status NOT NULL DEFAULT 'claimed',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL DEFAULT '',
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+    PRIMARY KEY (kind, subject, recipient)
+);
+
+INSERT INTO deliveries (kind, subject, recipient, status, sent_at)
+SELECT 'registration_reminder', telegram_id, telegram_id, 'sent', NOW()
+FROM users WHERE registration_notified = TRUE;
+
+INSERT INTO deliveries (kind, subject, recipient, status, sent_at)
+SELECT 'invite_reminder', telegram_id, telegram_id, 'sent', NOW()
+FROM users WHERE invite_notified = TRUE;
+
+INSERT INTO deliveries (kind, subject, recipient, status, claimed_at, sent_at)
+SELECT 'meeting_reminder:' || before_seconds, meeting_id, telegram_id, 'sent', sent_at, sent_at
+FROM meeting_reminders;
+
+ALTER TABLE users DROP COLUMN registration_notified;
+ALTER TABLE users DROP COLUMN invite_notified;
+DROP TABLE meeting_reminders;
+
+-- +goose Down
+CREATE TABLE meeting_reminders (
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    telegram_id BIGINT NOT NULL REFERENCES users(telegram_id),
+    before_seconds INTEGER NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (meeting_id, telegram_id, before_seconds)
+);
+
+ALTER TABLE users ADD COLUMN registration_notified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN invite_notified BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users SET registration_notified = TRUE
+WHERE telegram_id IN (SELECT recipient FROM deliveries WHERE kind = 'registration_reminder' AND status = 'sent');
+
+UPDATE users SET invite_notified = TRUE
+WHERE telegram_id IN (SELECT recipient FROM deliveries WHERE kind = 'invite_reminder' AND status = 'sent');
+
+INSERT INTO meeting_reminders (meeting_id, telegram_id, before_seconds, sent_at)
+SELECT subject, recipient, substring(kind FROM 'meeting_reminder:(\d+)')::INTEGER, COALESCE(sent_at, claimed_at)
+FROM deliveries WHERE kind LIKE 'meeting_reminder:%' AND status = 'sent'
+  AND subject IN (SELECT id FROM meetings);
+
+DROP TABLE IF EXISTS deliveries;
+DROP TYPE IF EXISTS delivery_status;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE notification_mutes (
+    telegram_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    category TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (telegram_id, category)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_mutes;
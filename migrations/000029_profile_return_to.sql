@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN return_to user_state;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN return_to;
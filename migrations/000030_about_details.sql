@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TYPE user_state ADD VALUE IF NOT EXISTS 'awaiting_about_details';
+
+-- +goose Down
+-- Note: cannot remove enum value in PostgreSQL
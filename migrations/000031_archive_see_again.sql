@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE meetings_archive ADD COLUMN see_again_asked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meetings_archive ADD COLUMN dill_see_again BOOLEAN;
+ALTER TABLE meetings_archive ADD COLUMN doe_see_again BOOLEAN;
+
+-- +goose Down
+ALTER TABLE meetings_archive DROP COLUMN doe_see_again;
+ALTER TABLE meetings_archive DROP COLUMN dill_see_again;
+ALTER TABLE meetings_archive DROP COLUMN see_again_asked;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Meetings are moved to the archive at every match, the relay log has to
+-- outlive them for abuse reports. Sessions of cleared meetings are closed
+-- when the participant writes next.
+ALTER TABLE relay_messages DROP CONSTRAINT relay_messages_meeting_id_fkey;
+ALTER TABLE relay_sessions DROP CONSTRAINT relay_sessions_meeting_id_fkey;
+
+-- +goose Down
+DELETE FROM relay_sessions WHERE meeting_id NOT IN (SELECT id FROM meetings);
+DELETE FROM relay_messages WHERE meeting_id NOT IN (SELECT id FROM meetings);
+ALTER TABLE relay_sessions ADD CONSTRAINT relay_sessions_meeting_id_fkey
+    FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE;
+ALTER TABLE relay_messages ADD CONSTRAINT relay_messages_meeting_id_fkey
+    FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE;
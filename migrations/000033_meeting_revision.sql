@@ -0,0 +1,7 @@
+-- +goose Up
+-- Bumped on every reschedule, so calendar files sent for the meeting carry an
+-- increasing SEQUENCE and replace the ones sent before.
+ALTER TABLE meetings ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE meetings DROP COLUMN revision;
@@ -0,0 +1,27 @@
+-- +goose Up
+INSERT INTO deliveries (kind, subject, recipient, status, sent_at)
+SELECT 'feedback_request', id, dill_id, 'sent', NOW()
+FROM meetings WHERE dill_feedback_requested = TRUE;
+
+INSERT INTO deliveries (kind, subject, recipient, status, sent_at)
+SELECT 'feedback_request', id, doe_id, 'sent', NOW()
+FROM meetings WHERE doe_feedback_requested = TRUE;
+
+-- deliveries are now recorded in the transaction that queues them, so only
+-- sent ones are kept; failed ones are dropped to be sent on the next run
+DELETE FROM deliveries WHERE status = 'failed';
+
+ALTER TABLE meetings DROP COLUMN dill_feedback_requested;
+ALTER TABLE meetings DROP COLUMN doe_feedback_requested;
+
+-- +goose Down
+ALTER TABLE meetings ADD COLUMN dill_feedback_requested BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meetings ADD COLUMN doe_feedback_requested BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE meetings SET dill_feedback_requested = TRUE
+WHERE id IN (SELECT subject FROM deliveries WHERE kind = 'feedback_request' AND recipient = meetings.dill_id);
+
+UPDATE meetings SET doe_feedback_requested = TRUE
+WHERE id IN (SELECT subject FROM deliveries WHERE kind = 'feedback_request' AND recipient = meetings.doe_id);
+
+DELETE FROM deliveries WHERE kind = 'feedback_request';
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE deliveries ADD COLUMN id BIGSERIAL;
+ALTER TABLE deliveries ADD CONSTRAINT deliveries_id_key UNIQUE (id);
+
+ALTER TABLE outbox ADD COLUMN delivery_id BIGINT REFERENCES deliveries(id) ON DELETE SET NULL;
+
+CREATE INDEX outbox_delivery_idx ON outbox (delivery_id) WHERE delivery_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS outbox_delivery_idx;
+ALTER TABLE outbox DROP COLUMN delivery_id;
+ALTER TABLE deliveries DROP COLUMN id;